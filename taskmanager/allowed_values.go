@@ -0,0 +1,111 @@
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Values accepted by the TaskManager-Go backend. Custom deployments can
+// override them through the provider's allowed_* arguments.
+var (
+	defaultStatuses   = []string{"To do", "In progress", "Done"}
+	defaultPriorities = []string{"High", "Medium", "Low"}
+	defaultRoles      = []string{"Admin", "Member"}
+)
+
+// validateAllowedValue is a CustomizeDiff function that checks key against
+// the list returned by allowed. Values differing only in case are accepted.
+func validateAllowedValue(key string, allowed func(*TaskManagerClient) []string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.HasChange(key) || !d.NewValueKnown(key) {
+			return nil
+		}
+
+		value := d.Get(key).(string)
+		if value == "" {
+			return nil
+		}
+
+		return checkAllowedValue(key, value, allowed(m.(*TaskManagerClient)))
+	}
+}
+
+func checkAllowedValue(key, value string, allowed []string) error {
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return nil
+		}
+	}
+
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = fmt.Sprintf("%q", a)
+	}
+
+	if suggestion := closestValue(value, allowed); suggestion != "" {
+		return fmt.Errorf("%s: %q is not an allowed value, did you mean %q? Allowed values are %s", key, value, suggestion, strings.Join(quoted, ", "))
+	}
+	return fmt.Errorf("%s: %q is not an allowed value. Allowed values are %s", key, value, strings.Join(quoted, ", "))
+}
+
+// canonicalValue returns the allowed spelling of value, so "in progress" is
+// sent to the API as "In progress".
+func canonicalValue(value string, allowed []string) string {
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return a
+		}
+	}
+	return value
+}
+
+func suppressCaseDifference(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// closestValue returns the allowed value nearest to value by edit distance,
+// or "" when nothing is close enough to be a plausible typo.
+func closestValue(value string, allowed []string) string {
+	best, bestDistance := "", -1
+	for _, a := range allowed {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(a))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = a, distance
+		}
+	}
+
+	limit := len(best) / 3
+	if limit < 2 {
+		limit = 2
+	}
+	if bestDistance < 0 || bestDistance > limit {
+		return ""
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,200 @@
+package taskmanager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// archiveFormats are the formats a source_dir can be packed into.
+var archiveFormats = []string{"zip", "tar.gz"}
+
+// archiveModTime is the modification time of every archive entry, so the
+// archive only changes when the files in it do. It is the earliest time a
+// zip file can hold.
+var archiveModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// archiveEntry is a file or directory to pack, relative to the packed
+// directory and with forward slashes.
+type archiveEntry struct {
+	name string
+	path string
+	dir  bool
+	mode fs.FileMode
+}
+
+// packDirectory writes the contents of dir to w as an archive of the given
+// format. Entries are written in lexical order with fixed times, owners and
+// modes, so packing the same files always gives the same bytes.
+func packDirectory(w io.Writer, dir string, format string) error {
+	entries, err := archiveEntries(dir)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "zip":
+		return packZip(w, entries)
+	case "tar.gz":
+		return packTarGz(w, entries)
+	}
+	return fmt.Errorf("unsupported archive format %q", format)
+}
+
+// archiveEntries lists the files and directories below dir. Symbolic links
+// to files are followed. Links to directories are not, as they could loop,
+// and neither they nor other special files can be packed.
+func archiveEntries(dir string) ([]archiveEntry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var entries []archiveEntry
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		entry := archiveEntry{name: filepath.ToSlash(rel), path: path}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			entry.dir = true
+			entry.mode = fs.ModeDir | 0755
+		case info.Mode().IsRegular():
+			// Only the executable bit is kept, so umask and ownership
+			// differences between machines do not change the archive.
+			entry.mode = 0644
+			if info.Mode()&0111 != 0 {
+				entry.mode = 0755
+			}
+		case info.IsDir():
+			return fmt.Errorf("%s is a symbolic link to a directory, which cannot be archived", path)
+		default:
+			return fmt.Errorf("%s cannot be archived, only files, links to files and directories can", path)
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func packZip(w io.Writer, entries []archiveEntry) error {
+	zw := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:     entry.name,
+			Method:   zip.Deflate,
+			Modified: archiveModTime,
+		}
+		if entry.dir {
+			header.Name += "/"
+			header.Method = zip.Store
+		}
+		header.SetMode(entry.mode)
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if !entry.dir {
+			if err := copyFile(fw, entry.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+func packTarGz(w io.Writer, entries []archiveEntry) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    int64(entry.mode.Perm()),
+			ModTime: archiveModTime,
+			Format:  tar.FormatPAX,
+		}
+		if entry.dir {
+			header.Typeflag = tar.TypeDir
+			header.Name += "/"
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		header.Typeflag = tar.TypeReg
+		if err := writeTarFile(tw, header, entry.path); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeTarFile writes the file at path to tw. The size in the header is the
+// size of the open file, and only that much is copied, so a file that is
+// still being written, such as a build log, is archived as it was when it
+// was opened.
+func writeTarFile(tw *tar.Writer, header *tar.Header, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header.Size = info.Size()
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, file, header.Size); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,140 @@
+package taskmanager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testArchiveDir(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0700); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for name, mode := range map[string]fs.FileMode{
+		"README.md":    0600,
+		"bin/build.sh": 0700,
+		"config.yaml":  0664,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), mode); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		// WriteFile is subject to the umask.
+		if err := os.Chmod(filepath.Join(dir, name), mode); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	return dir
+}
+
+func testPackDirectory(t *testing.T, dir string, format string) []byte {
+	var archive bytes.Buffer
+	if err := packDirectory(&archive, dir, format); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return archive.Bytes()
+}
+
+// testArchiveModes returns the mode of every entry of a packed archive, keyed
+// by its name.
+func testArchiveModes(t *testing.T, archive []byte, format string) map[string]fs.FileMode {
+	modes := map[string]fs.FileMode{}
+	switch format {
+	case "zip":
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for _, file := range zr.File {
+			modes[file.Name] = file.Mode()
+		}
+	case "tar.gz":
+		gr, err := gzip.NewReader(bytes.NewReader(archive))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		tr := tar.NewReader(gr)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			modes[header.Name] = header.FileInfo().Mode()
+		}
+	}
+	return modes
+}
+
+func TestPackDirectoryStable(t *testing.T) {
+	for _, format := range archiveFormats {
+		dir := testArchiveDir(t)
+		first := testPackDirectory(t, dir, format)
+
+		touched := time.Now().Add(time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, "README.md"), touched, touched); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !bytes.Equal(testPackDirectory(t, dir, format), first) {
+			t.Fatalf("%s: a new modification time changed the archive", format)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("edited"), 0600); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if bytes.Equal(testPackDirectory(t, dir, format), first) {
+			t.Fatalf("%s: an edited file did not change the archive", format)
+		}
+	}
+}
+
+func TestPackDirectoryModes(t *testing.T) {
+	dir := testArchiveDir(t)
+
+	for _, format := range archiveFormats {
+		modes := testArchiveModes(t, testPackDirectory(t, dir, format), format)
+
+		expected := map[string]fs.FileMode{
+			"README.md":    0644,
+			"bin/":         fs.ModeDir | 0755,
+			"bin/build.sh": 0755,
+			"config.yaml":  0644,
+		}
+		if len(modes) != len(expected) {
+			t.Fatalf("%s: got entries %v", format, modes)
+		}
+		for name, mode := range expected {
+			if modes[name] != mode {
+				t.Fatalf("%s: %s has mode %s, expected %s", format, name, modes[name], mode)
+			}
+		}
+	}
+}
+
+func TestPackDirectoryLinks(t *testing.T) {
+	dir := testArchiveDir(t)
+	if err := os.Symlink("README.md", filepath.Join(dir, "LINK.md")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	modes := testArchiveModes(t, testPackDirectory(t, dir, "zip"), "zip")
+	if modes["LINK.md"] != 0644 {
+		t.Fatalf("link to a file not archived as a file: %v", modes)
+	}
+
+	if err := os.Symlink("bin", filepath.Join(dir, "tools")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := packDirectory(io.Discard, dir, "zip"); err == nil {
+		t.Fatal("expected an error for a link to a directory")
+	}
+}
@@ -0,0 +1,229 @@
+package taskmanager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// attachmentPolicy restricts what attachments may be uploaded. Its zero value
+// allows everything.
+type attachmentPolicy struct {
+	maxSize         int64
+	contentTypes    []string
+	extensions      []string
+	denyExecutables bool
+}
+
+// sizeUnits are the suffixes accepted by parseSize, longest first so "MB" is
+// not taken for "B".
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"B", 1},
+}
+
+// executableMagic are the leading bytes of native executables and scripts:
+// ELF, Windows PE, Mach-O in both byte orders and universal binaries, and
+// shebang lines.
+var executableMagic = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce},
+	{0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe},
+	{0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+	[]byte("#!"),
+}
+
+func attachmentPolicySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_size": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"allowed_content_types": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"allowed_extensions": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"deny_executables": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+func expandAttachmentPolicy(raw []interface{}) (attachmentPolicy, error) {
+	var policy attachmentPolicy
+	if len(raw) == 0 || raw[0] == nil {
+		return policy, nil
+	}
+	block := raw[0].(map[string]interface{})
+
+	if v := block["max_size"].(string); v != "" {
+		size, err := parseSize(v)
+		if err != nil {
+			return policy, fmt.Errorf("attachment_policy.max_size: %w", err)
+		}
+		policy.maxSize = size
+	}
+
+	for _, t := range block["allowed_content_types"].([]interface{}) {
+		contentType := strings.ToLower(t.(string))
+		if _, err := path.Match(contentType, ""); err != nil || !strings.Contains(contentType, "/") {
+			return policy, fmt.Errorf("attachment_policy.allowed_content_types: %q is not a MIME type such as \"image/png\" or \"image/*\"", t)
+		}
+		policy.contentTypes = append(policy.contentTypes, contentType)
+	}
+
+	for _, e := range block["allowed_extensions"].([]interface{}) {
+		policy.extensions = append(policy.extensions, "."+strings.TrimPrefix(strings.ToLower(e.(string)), "."))
+	}
+
+	policy.denyExecutables = block["deny_executables"].(bool)
+
+	return policy, nil
+}
+
+// check returns an error naming the first rule of the policy that the content
+// breaks. The body is rewound afterwards.
+func (p attachmentPolicy) check(fileName string, contentType string, body *attachmentBody) error {
+	if p.maxSize > 0 && body.size > p.maxSize {
+		return fmt.Errorf("%s is %s, attachment_policy allows at most %s", fileName, formatSize(body.size), formatSize(p.maxSize))
+	}
+
+	if len(p.extensions) > 0 {
+		ext := strings.ToLower(filepath.Ext(fileName))
+		if !slices.Contains(p.extensions, ext) {
+			return fmt.Errorf("%s has an extension that attachment_policy does not allow, allowed are %s", fileName, strings.Join(p.extensions, ", "))
+		}
+	}
+
+	if len(p.contentTypes) == 0 && !p.denyExecutables {
+		return nil
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head = head[:n]
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if p.denyExecutables && isExecutable(head) {
+		return fmt.Errorf("%s is an executable, which attachment_policy denies", fileName)
+	}
+
+	if len(p.contentTypes) > 0 {
+		if !p.allowsContentType(contentType) {
+			return fmt.Errorf("%s has type %s, which attachment_policy does not allow", fileName, contentType)
+		}
+		// The extension alone is easily wrong, so the type detected from
+		// the content has to be allowed as well. Plain text and unknown
+		// binary data say too little to hold against the file.
+		sniffed := http.DetectContentType(head)
+		if !isGenericContentType(sniffed) && !p.allowsContentType(sniffed) {
+			return fmt.Errorf("%s contains %s, which attachment_policy does not allow", fileName, sniffed)
+		}
+	}
+
+	return nil
+}
+
+func (p attachmentPolicy) allowsContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+
+	for _, allowed := range p.contentTypes {
+		if ok, _ := path.Match(allowed, mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenericContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/octet-stream" || mediaType == "text/plain"
+}
+
+func isExecutable(head []byte) bool {
+	for _, magic := range executableMagic {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSize parses a size such as "1048576", "500KB" or "100MiB".
+func parseSize(s string) (int64, error) {
+	number := strings.TrimSpace(s)
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(number), strings.ToUpper(unit.suffix)) {
+			number = strings.TrimRightFunc(number[:len(number)-len(unit.suffix)], unicode.IsSpace)
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not a size such as \"500KB\" or \"100MiB\"", s)
+	}
+	return n * multiplier, nil
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1000*1000*1000:
+		return fmt.Sprintf("%.1f GB", float64(size)/1e9)
+	case size >= 1000*1000:
+		return fmt.Sprintf("%.1f MB", float64(size)/1e6)
+	case size >= 1000:
+		return fmt.Sprintf("%.1f KB", float64(size)/1e3)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
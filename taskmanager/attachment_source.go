@@ -0,0 +1,274 @@
+package taskmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// attachmentSourceKeys are the mutually exclusive arguments an attachment's
+// content can come from. url is the deprecated name of source_path.
+var attachmentSourceKeys = []string{"source_path", "url", "content", "content_base64", "source_url", "source_dir"}
+
+// sniffLength is the number of leading bytes used to detect a MIME type, the
+// most http.DetectContentType looks at.
+const sniffLength = 512
+
+// attachmentConfig is implemented by both schema.ResourceData and
+// schema.ResourceDiff, so sources are read the same way at plan and apply
+// time.
+type attachmentConfig interface {
+	GetOk(key string) (interface{}, bool)
+}
+
+// attachmentSource is where an attachment's content comes from. Exactly one
+// of path, data, remoteURL and dir is set.
+type attachmentSource struct {
+	// key is the argument the source was given in.
+	key string
+
+	path string
+	data []byte
+
+	remoteURL string
+	// remoteSHA256 is the expected hex digest of remoteURL, if known.
+	remoteSHA256 string
+
+	// dir is packed into an archive of archiveFormat.
+	dir           string
+	archiveFormat string
+}
+
+func expandAttachmentSource(d attachmentConfig) (attachmentSource, error) {
+	if v, ok := d.GetOk("source_path"); ok {
+		return attachmentSource{key: "source_path", path: v.(string)}, nil
+	}
+	if v, ok := d.GetOk("url"); ok {
+		return attachmentSource{key: "url", path: v.(string)}, nil
+	}
+	if v, ok := d.GetOk("content"); ok {
+		return attachmentSource{key: "content", data: []byte(v.(string))}, nil
+	}
+	if v, ok := d.GetOk("content_base64"); ok {
+		data, err := base64.StdEncoding.DecodeString(v.(string))
+		if err != nil {
+			return attachmentSource{}, fmt.Errorf("content_base64: %w", err)
+		}
+		return attachmentSource{key: "content_base64", data: data}, nil
+	}
+	if v, ok := d.GetOk("source_url"); ok {
+		sum, _ := d.GetOk("source_sha256")
+		return attachmentSource{
+			key:          "source_url",
+			remoteURL:    v.(string),
+			remoteSHA256: strings.ToLower(sum.(string)),
+		}, nil
+	}
+
+	if v, ok := d.GetOk("source_dir"); ok {
+		format, _ := d.GetOk("archive_format")
+		return attachmentSource{key: "source_dir", dir: v.(string), archiveFormat: format.(string)}, nil
+	}
+
+	return attachmentSource{}, fmt.Errorf("one of %s must be set", strings.Join(attachmentSourceKeys, ", "))
+}
+
+// fileName infers the name to upload the content under: the base name of the
+// path, URL or archived directory, or a generic name with an extension
+// matching inline content.
+func (s attachmentSource) fileName() string {
+	switch {
+	case s.path != "":
+		return filepath.Base(s.path)
+	case s.dir != "":
+		name := filepath.Base(filepath.Clean(s.dir))
+		if name == "." || name == string(filepath.Separator) {
+			name = "attachment"
+		}
+		return name + "." + s.archiveFormat
+	case s.remoteURL != "":
+		if u, err := url.Parse(s.remoteURL); err == nil {
+			if name := path.Base(u.Path); name != "." && name != "/" {
+				return name
+			}
+		}
+	case s.key == "content":
+		return "attachment.txt"
+	case s.data != nil:
+		return "attachment" + extensionForType(http.DetectContentType(s.data))
+	}
+	return "attachment"
+}
+
+// open returns the content to upload. Remote content is downloaded and
+// directories are packed to a temporary file first, so the checksum is known
+// before anything is uploaded.
+func (s attachmentSource) open(ctx context.Context, client *TaskManagerClient) (*attachmentBody, error) {
+	switch {
+	case s.path != "":
+		absPath, err := filepath.Abs(filepath.Clean(s.path))
+		if err != nil {
+			return nil, err
+		}
+		sum, size, err := fileDigest(absPath)
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.Open(absPath)
+		if err != nil {
+			return nil, err
+		}
+		return &attachmentBody{ReadSeeker: file, size: size, sha256: sum, close: file.Close}, nil
+
+	case s.remoteURL != "":
+		return s.download(ctx, client)
+
+	case s.dir != "":
+		return s.archive()
+	}
+
+	sum := sha256.Sum256(s.data)
+	return &attachmentBody{
+		ReadSeeker: bytes.NewReader(s.data),
+		size:       int64(len(s.data)),
+		sha256:     hex.EncodeToString(sum[:]),
+		close:      func() error { return nil },
+	}, nil
+}
+
+func (s attachmentSource) download(ctx context.Context, client *TaskManagerClient) (*attachmentBody, error) {
+	// The source is a third-party URL, so the API token is not sent along.
+	req, err := http.NewRequestWithContext(ctx, "GET", s.remoteURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO] downloading attachment source %s", s.remoteURL)
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", s.remoteURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("unable to fetch %s: status %d", s.remoteURL, resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp("", "taskmanager-attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error {
+		tmp.Close()
+		return os.Remove(tmp.Name())
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("unable to fetch %s: %w", s.remoteURL, err)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if s.remoteSHA256 != "" && sum != s.remoteSHA256 {
+		cleanup()
+		return nil, fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s", s.remoteURL, s.remoteSHA256, sum)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return &attachmentBody{ReadSeeker: tmp, size: size, sha256: sum, close: cleanup}, nil
+}
+
+// archive packs the source directory into a temporary file.
+func (s attachmentSource) archive() (*attachmentBody, error) {
+	// Stat first so a missing directory is reported before a temporary
+	// file is created.
+	if _, err := os.Stat(s.dir); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "taskmanager-attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error {
+		tmp.Close()
+		return os.Remove(tmp.Name())
+	}
+
+	hash := sha256.New()
+	if err := packDirectory(io.MultiWriter(tmp, hash), s.dir, s.archiveFormat); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("unable to archive %s: %w", s.dir, err)
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return &attachmentBody{ReadSeeker: tmp, size: size, sha256: hex.EncodeToString(hash.Sum(nil)), close: cleanup}, nil
+}
+
+// attachmentBody is opened attachment content together with its digest.
+type attachmentBody struct {
+	io.ReadSeeker
+	size   int64
+	sha256 string
+	close  func() error
+}
+
+func (b *attachmentBody) Close() error {
+	return b.close()
+}
+
+// contentType returns the MIME type of the body, from the file name's
+// extension or else from its leading bytes. The body is rewound afterwards.
+func (b *attachmentBody) contentType(fileName string) (string, error) {
+	if t := mime.TypeByExtension(filepath.Ext(fileName)); t != "" {
+		return t, nil
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(b, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := b.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+func extensionForType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "text/plain" {
+		return ".txt"
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
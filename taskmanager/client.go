@@ -2,154 +2,196 @@ package taskmanager
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type TaskManagerClient struct {
 	baseURL    string
 	token      string
 	HTTPClient *http.Client
+
+	// defaultLocation is used for due dates given without a time of day.
+	defaultLocation *time.Location
+
+	allowedStatuses   []string
+	allowedPriorities []string
+	allowedRoles      []string
+
+	workflows []taskWorkflow
+
+	attachmentPolicy attachmentPolicy
+
+	// attachmentEncryption is nil unless the provider configures it.
+	attachmentEncryption *attachmentEncryption
+
+	// uploadTimeout bounds a single attachment upload or download, zero means
+	// no limit.
+	uploadTimeout time.Duration
 }
 
 func NewClient(baseURL string, token string) *TaskManagerClient {
 	return &TaskManagerClient{
-		baseURL:    baseURL,
-		token:      token,
-		HTTPClient: &http.Client{},
+		baseURL:           baseURL,
+		token:             token,
+		HTTPClient:        &http.Client{},
+		defaultLocation:   time.UTC,
+		allowedStatuses:   defaultStatuses,
+		allowedPriorities: defaultPriorities,
+		allowedRoles:      defaultRoles,
+		uploadTimeout:     defaultUploadTimeout,
 	}
 }
 
-func (c *TaskManagerClient) Get(endPoint string, result interface{}) error {
-	req, err := http.NewRequest("GET", c.baseURL+endPoint, nil)
-	if err != nil {
-		return err
+// tokenUserID returns the user_id claim of the token. The claims are only
+// decoded, verifying the signature is left to the backend.
+func (c *TaskManagerClient) tokenUserID() (string, error) {
+	parts := strings.Split(c.token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("token is not a JWT")
 	}
-	auth_token := "Bearer " + c.token
-	req.Header.Set("Authorization", auth_token)
-	log.Println(auth_token)
-	resp, err := c.HTTPClient.Do(req)
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
 	if err != nil {
-		return err
+		return "", fmt.Errorf("unable to decode token claims: %w", err)
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		var errResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			if msg, ok := errResp["error"].(string); ok {
-				return fmt.Errorf("API error: %s", msg)
-			}
-		}
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("unable to decode token claims: %w", err)
 	}
 
-	return json.NewDecoder(resp.Body).Decode(result)
-}
-
-func (c *TaskManagerClient) Post(endPoint string, body interface{}, result interface{}) error {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return err
+	switch userID := claims["user_id"].(type) {
+	case float64:
+		return strconv.Itoa(int(userID)), nil
+	case string:
+		return userID, nil
 	}
+	return "", fmt.Errorf("token has no user_id claim")
+}
 
-	req, err := http.NewRequest("POST", c.baseURL+endPoint, bytes.NewBuffer(jsonBody))
+func (c *TaskManagerClient) Get(endPoint string, result interface{}) error {
+	req, err := c.newRequest(context.Background(), "GET", endPoint, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
+	return c.do(req, result)
+}
 
-	defer resp.Body.Close()
+func (c *TaskManagerClient) Post(endPoint string, body interface{}, result interface{}) error {
+	return c.sendJSON("POST", endPoint, body, result)
+}
 
-	if resp.StatusCode >= 400 {
-		var errResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			if msg, ok := errResp["error"].(string); ok {
-				return fmt.Errorf("API error: %s", msg)
-			}
-		}
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-	}
+func (c *TaskManagerClient) Put(endPoint string, body interface{}, result interface{}) error {
+	return c.sendJSON("PUT", endPoint, body, result)
+}
 
-	if result != nil {
-		return json.NewDecoder(resp.Body).Decode(result)
+func (c *TaskManagerClient) Delete(endPoint string) error {
+	req, err := c.newRequest(context.Background(), "DELETE", endPoint, nil)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return c.do(req, nil)
 }
 
-func (c *TaskManagerClient) Put(endPoint string, body interface{}, result interface{}) error {
+func (c *TaskManagerClient) sendJSON(method string, endPoint string, body interface{}, result interface{}) error {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("PUT", c.baseURL+endPoint, bytes.NewBuffer(jsonBody))
+	req, err := c.newRequest(context.Background(), method, endPoint, bytes.NewReader(jsonBody))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, result)
+}
 
+// newRequest builds an authenticated request for an API endpoint.
+func (c *TaskManagerClient) newRequest(ctx context.Context, method string, endPoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endPoint, body)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// send sends req and returns the response if it succeeded. The caller closes
+// the response body.
+func (c *TaskManagerClient) send(req *http.Request) (*http.Response, error) {
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer resp.Body.Close()
-
 	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+
+		apiErr := &apiError{status: resp.StatusCode, body: string(bodyBytes)}
 		var errResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			if msg, ok := errResp["error"].(string); ok {
-				return fmt.Errorf("API error: %s", msg)
-			}
+		if err := json.Unmarshal(bodyBytes, &errResp); err == nil {
+			apiErr.message, _ = errResp["error"].(string)
 		}
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return nil, apiErr
 	}
 
-	if result != nil {
-		return json.NewDecoder(resp.Body).Decode(&result)
-	}
+	return resp, nil
+}
 
-	return nil
+// apiError is an error response from the API.
+type apiError struct {
+	status int
+	// message is the API's error message, if the response had one.
+	message string
+	body    string
 }
 
-func (c *TaskManagerClient) Delete(endPoint string) error {
-	req, err := http.NewRequest("DELETE", c.baseURL+endPoint, nil)
-	if err != nil {
-		return err
+func (e *apiError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("API error: %s", e.message)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	return fmt.Sprintf("API error (status %d): %s", e.status, e.body)
+}
 
-	resp, err := c.HTTPClient.Do(req)
+// isNotFound reports whether err is the API saying the object does not
+// exist. GORM hides soft-deleted rows, so this is how most deletions made
+// outside Terraform show up. Some handlers pass GORM's "record not found"
+// through with another status.
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.status == http.StatusNotFound || strings.Contains(strings.ToLower(apiErr.message), "record not found")
+}
+
+// do sends req and decodes the JSON response into result, unless result is
+// nil. Error responses are reported with the API's error message if it has
+// one, otherwise with the status and the raw body.
+func (c *TaskManagerClient) do(req *http.Request, result interface{}) error {
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		var errResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			if msg, ok := errResp["error"].(string); ok {
-				return fmt.Errorf("API error: %s", msg)
-			}
-		}
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
 	}
 
 	return nil
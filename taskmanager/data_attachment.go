@@ -11,7 +11,7 @@ import (
 func dataAttachment() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: DataReadAttachment,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"id": {
 				Type:     schema.TypeInt,
 				Required: true,
@@ -32,7 +32,7 @@ func dataAttachment() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-		},
+		}),
 	}
 }
 
@@ -42,19 +42,17 @@ func DataReadAttachment(ctx context.Context, d *schema.ResourceData, m interface
 	idInt := d.Get("id").(int)
 	idStr := strconv.Itoa(idInt)
 
-	var attachment map[string]interface{}
-
-	if err := client.Get("api/attachments/"+idStr, &attachment); err != nil {
+	result, err := getAttachment(client, idStr)
+	if err != nil {
 		return diag.FromErr(err)
 	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("attachment %s has been deleted", idStr)
+	}
 
 	d.SetId(idStr)
 
-	result := attachment["attachment"].(map[string]interface{})
-
-	d.Set("file_name", result["file_name"])
-	d.Set("task_id", result["task_id"])
-	d.Set("uploader_id", result["uploader_id"])
+	flattenAttachment(d, result)
 
 	return nil
 }
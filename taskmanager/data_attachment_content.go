@@ -0,0 +1,235 @@
+package taskmanager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataAttachmentContent() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadAttachmentContent,
+		Schema: withTimestamps(map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"output_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"output_file_permission": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "0600",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^0?[0-7]{3}$`), "must be an octal file mode such as \"0600\""),
+			},
+			"decrypt": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"encrypted": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"file_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"task_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"uploader_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"content_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"content_base64": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		}),
+	}
+}
+
+func dataReadAttachmentContent(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	idStr := strconv.Itoa(d.Get("id").(int))
+
+	result, err := getAttachment(client, idStr)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("attachment %s has been deleted", idStr)
+	}
+
+	download := attachmentDownload{
+		endPoint: "api/attachments/" + idStr + "/download",
+		decrypt:  d.Get("decrypt").(bool),
+	}
+	hash := sha256.New()
+
+	if v, ok := d.GetOk("output_path"); ok {
+		// Large files are written straight to disk instead of into state.
+		outputPath := v.(string)
+		perm, _ := strconv.ParseUint(d.Get("output_file_permission").(string), 8, 32)
+		if err := downloadToFile(ctx, client, &download, outputPath, os.FileMode(perm), hash); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("content", "")
+		d.Set("content_base64", "")
+	} else {
+		var content bytes.Buffer
+		if err := download.run(ctx, client, io.MultiWriter(&content, hash)); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("content_base64", base64.StdEncoding.EncodeToString(content.Bytes()))
+		if utf8.Valid(content.Bytes()) {
+			d.Set("content", content.String())
+		} else {
+			log.Printf("[DEBUG] attachment %s is not valid UTF-8, only content_base64 is set", idStr)
+			d.Set("content", "")
+		}
+	}
+
+	d.SetId(idStr)
+	d.Set("content_type", download.header.Get("Content-Type"))
+	d.Set("encrypted", download.encrypted)
+	d.Set("size", download.size)
+	d.Set("sha256", hex.EncodeToString(hash.Sum(nil)))
+
+	flattenAttachment(d, result)
+
+	// Decrypted content is described by the name it was encrypted from.
+	if download.encrypted && download.decrypt {
+		fileName := strings.TrimSuffix(stringValue(result["file_name"]), encryptedSuffix)
+		d.Set("file_name", fileName)
+		if contentType := mime.TypeByExtension(filepath.Ext(fileName)); contentType != "" {
+			d.Set("content_type", contentType)
+		}
+	}
+
+	return nil
+}
+
+// attachmentDownload downloads an attachment's content, decrypting it on
+// the fly if it was encrypted and decrypt is set.
+type attachmentDownload struct {
+	endPoint string
+	decrypt  bool
+
+	header    http.Header
+	size      int64
+	encrypted bool
+}
+
+func (a *attachmentDownload) run(ctx context.Context, client *TaskManagerClient, w io.Writer) error {
+	// The content is decrypted while it is downloaded, so large files are
+	// not held in memory.
+	pr, pw := io.Pipe()
+	downloaded := make(chan error, 1)
+	go func() {
+		var err error
+		a.header, _, err = client.Download(ctx, a.endPoint, pw)
+		pw.CloseWithError(err)
+		downloaded <- err
+	}()
+
+	var content io.Reader
+	var err error
+	if a.decrypt {
+		content, a.encrypted, err = client.attachmentEncryption.decrypt(bufio.NewReader(pr))
+	} else {
+		br := bufio.NewReader(pr)
+		content, a.encrypted = br, isEncrypted(br)
+	}
+	if err == nil {
+		a.size, err = io.Copy(w, content)
+	}
+	// Stops the download if decrypting failed.
+	pr.CloseWithError(err)
+
+	if downloadErr := <-downloaded; downloadErr != nil && !errors.Is(downloadErr, io.ErrClosedPipe) {
+		return downloadErr
+	}
+	return err
+}
+
+// downloadToFile downloads an attachment to path with the given permissions.
+// The content is written to a temporary file next to path first, so path is
+// never left half written.
+func downloadToFile(ctx context.Context, client *TaskManagerClient, download *attachmentDownload, path string, perm os.FileMode, hash io.Writer) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("output_path: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("output_path: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	// Applied before any content is written, so the content is never
+	// readable more widely than output_file_permission allows.
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("output_path: %w", err)
+	}
+
+	if err = download.run(ctx, client, io.MultiWriter(tmp, hash)); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("output_path: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("output_path: %w", err)
+	}
+
+	return nil
+}
@@ -12,7 +12,7 @@ import (
 func dataComment() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataReadComment,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"id": {
 				Type:     schema.TypeInt,
 				Required: true,
@@ -40,7 +40,7 @@ func dataComment() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -50,23 +50,19 @@ func dataReadComment(ctx context.Context, d *schema.ResourceData, m interface{})
 	idInt := d.Get("id").(int)
 	idStr := strconv.Itoa(idInt)
 
-	var comment map[string]interface{}
-
 	log.Println("[INFO] Getting Comment")
 
-	if err := client.Get("api/comments/"+idStr, &comment); err != nil {
+	result, err := getComment(client, idStr)
+	if err != nil {
 		return diag.FromErr(err)
 	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("comment %s has been deleted", idStr)
+	}
 
 	d.SetId(idStr)
 
-	result := comment["comment"].(map[string]interface{})
-
-	d.Set("content", result["content"])
-	d.Set("user_id", result["user_id"])
-	d.Set("task_id", result["task_id"])
-	d.Set("parent_comment_id", result["parent_comment_id"])
-	d.Set("subcomments", result["subcomments"])
+	flattenComment(d, result)
 
 	return nil
 }
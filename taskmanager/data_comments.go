@@ -0,0 +1,219 @@
+package taskmanager
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// commentThreadDepth is the number of nesting levels of the comments
+// attribute. Deeper replies are still listed in reply_ids and ids.
+const commentThreadDepth = 5
+
+func dataComments() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadComments,
+		Schema: map[string]*schema.Schema{
+			"task_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"author_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"created_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDueDate,
+			},
+			"created_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDueDate,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"comments": commentThreadSchema(commentThreadDepth),
+		},
+	}
+}
+
+func commentThreadSchema(depth int) *schema.Schema {
+	fields := map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"user_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"author_uname": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"content": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"parent_comment_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"reply_ids": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		},
+	}
+	if depth > 1 {
+		fields["replies"] = commentThreadSchema(depth - 1)
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+func dataReadComments(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	taskID := strconv.Itoa(d.Get("task_id").(int))
+	task, err := getTask(client, taskID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var filters []func(map[string]interface{}) bool
+	if v, ok := d.GetOk("author_id"); ok {
+		filters = append(filters, func(comment map[string]interface{}) bool {
+			return intValue(comment["user_id"]) == v.(int)
+		})
+	}
+	if v, ok := d.GetOk("created_after"); ok {
+		after, err := parseDueDate(v.(string), client.defaultLocation)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		filters = append(filters, func(comment map[string]interface{}) bool {
+			created, ok := timestampValue(comment, createdAtKeys...)
+			return ok && created.After(after)
+		})
+	}
+	if v, ok := d.GetOk("created_before"); ok {
+		before, err := parseDueDate(v.(string), client.defaultLocation)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		filters = append(filters, func(comment map[string]interface{}) bool {
+			created, ok := timestampValue(comment, createdAtKeys...)
+			return ok && created.Before(before)
+		})
+	}
+
+	// The task may list replies next to their parents or only nested in
+	// their subcomments, so collect every comment once before building the
+	// tree from parent_comment_id.
+	comments := map[int]map[string]interface{}{}
+	collectComments(task["comments"], comments)
+
+	var matched []map[string]interface{}
+	for _, comment := range comments {
+		if matchesAll(comment, filters) {
+			matched = append(matched, comment)
+		}
+	}
+	matched = sortByID(matched)
+
+	// Replies whose parent was filtered out are shown at the top level.
+	children := map[int][]map[string]interface{}{}
+	included := map[int]bool{}
+	ids := []int{}
+	for _, comment := range matched {
+		id, _ := objectID(comment)
+		included[id] = true
+		ids = append(ids, id)
+	}
+	for _, comment := range matched {
+		parentID := intValue(comment["parent_comment_id"])
+		if !included[parentID] {
+			parentID = 0
+		}
+		children[parentID] = append(children[parentID], comment)
+	}
+
+	if err := d.Set("comments", flattenCommentThread(children, 0, commentThreadDepth)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("ids", ids)
+	d.SetId(taskID)
+
+	return nil
+}
+
+func collectComments(raw interface{}, comments map[int]map[string]interface{}) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, r := range list {
+		comment, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := objectID(comment); ok && !isSoftDeleted(comment) {
+			if _, seen := comments[id]; !seen {
+				comments[id] = comment
+			}
+		}
+		collectComments(comment["subcomments"], comments)
+	}
+}
+
+func flattenCommentThread(children map[int][]map[string]interface{}, parentID int, depth int) []interface{} {
+	thread := make([]interface{}, 0, len(children[parentID]))
+	for _, comment := range children[parentID] {
+		item := flattenCommentSummary(comment)
+		id := item["id"].(int)
+
+		item["created_at"] = formatTimestamp(comment, createdAtKeys...)
+		item["updated_at"] = formatTimestamp(comment, updatedAtKeys...)
+
+		replyIDs := []int{}
+		for _, reply := range children[id] {
+			replyID, _ := objectID(reply)
+			replyIDs = append(replyIDs, replyID)
+		}
+		item["reply_ids"] = replyIDs
+
+		if depth > 1 {
+			item["replies"] = flattenCommentThread(children, id, depth-1)
+		}
+
+		thread = append(thread, item)
+	}
+	return thread
+}
@@ -0,0 +1,50 @@
+package taskmanager
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataCurrentUser resolves the user the provider's token belongs to. It has
+// the attributes of the taskmanager_user data source, all of them computed.
+func dataCurrentUser() *schema.Resource {
+	userSchema := dataUser().Schema
+	delete(userSchema, "id")
+	delete(userSchema, "password")
+	for _, attr := range userSchema {
+		attr.Optional = false
+		attr.ExactlyOneOf = nil
+		attr.Computed = true
+	}
+
+	return &schema.Resource{
+		ReadContext: dataReadCurrentUser,
+		Schema:      userSchema,
+	}
+}
+
+func dataReadCurrentUser(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	idStr, err := client.tokenUserID()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result, err := getUser(client, idStr)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("user %s has been deleted", idStr)
+	}
+
+	d.SetId(idStr)
+
+	flattenUser(d, result)
+	flattenUserDetails(d, result)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataLabel() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadLabel,
+		Schema: withTimestamps(map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"team_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"global_only"},
+			},
+			"global_only": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"team_id"},
+			},
+			"color": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		}),
+	}
+}
+
+func dataReadLabel(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	idStr, err := lookupLabelID(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result, err := getLabel(client, idStr)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("label %s has been deleted", idStr)
+	}
+
+	d.SetId(idStr)
+
+	flattenLabel(d, result)
+
+	return nil
+}
+
+// lookupLabelID returns the ID of the label selected by id, or by name and
+// optionally team_id or global_only. Teams may each have a label of the same
+// name, and so may labels available to every team, which have no team_id.
+func lookupLabelID(client *TaskManagerClient, d *schema.ResourceData) (string, error) {
+	if id, ok := d.GetOk("id"); ok {
+		return strconv.Itoa(id.(int)), nil
+	}
+
+	labels, err := listObjects(client, "api/labels", "labels")
+	if err != nil {
+		return "", err
+	}
+
+	name := d.Get("name").(string)
+	criteria := fmt.Sprintf("name %q", name)
+	teamID, byTeam := d.GetOk("team_id")
+	global := d.Get("global_only").(bool)
+	switch {
+	case byTeam:
+		criteria += fmt.Sprintf(" in team %d", teamID.(int))
+	case global:
+		criteria += " outside of teams"
+	}
+
+	return findOne(labels, "label", criteria, func(label map[string]interface{}) bool {
+		if label["name"] != name {
+			return false
+		}
+		switch {
+		case byTeam:
+			return intValue(label["team_id"]) == teamID.(int)
+		case global:
+			return intValue(label["team_id"]) == 0
+		}
+		return true
+	})
+}
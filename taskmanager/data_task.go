@@ -2,6 +2,7 @@ package taskmanager
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -11,14 +12,18 @@ import (
 func dataTask() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataReadTask,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "title"},
 			},
 			"title": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				RequiredWith: []string{"team_id"},
 			},
 			"description": {
 				Type:     schema.TypeString,
@@ -42,6 +47,7 @@ func dataTask() *schema.Resource {
 			},
 			"team_id": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"parent_task_id": {
@@ -83,40 +89,62 @@ func dataTask() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+			"assignee_details": userSummarySchema(),
+			"label_details":    labelSummarySchema(),
+			"subtask_details":  taskSummarySchema(),
+			"comment_details":  commentSummarySchema(),
+		}),
 	}
 }
 
 func dataReadTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	idInt := d.Get("id").(int)
-	idStr := strconv.Itoa(idInt)
+	idStr, err := lookupTaskID(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	var task map[string]interface{}
-	if err := client.Get("api/tasks/"+idStr, &task); err != nil {
+	result, err := getTask(client, idStr)
+	if err != nil {
 		return diag.FromErr(err)
 	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("task %s has been deleted", idStr)
+	}
 
 	d.SetId(idStr)
 
-	result, ok := task["task"].(map[string]interface{})
-	if !ok {
-		return diag.Errorf("unable to get team")
+	flattenTask(d, result, client)
+	flattenTaskDetails(d, result)
+
+	return nil
+}
+
+// lookupTaskID returns the ID of the task selected by id, or by title within
+// the team given by team_id.
+func lookupTaskID(client *TaskManagerClient, d *schema.ResourceData) (string, error) {
+	if id, ok := d.GetOk("id"); ok {
+		return strconv.Itoa(id.(int)), nil
 	}
 
-	d.Set("title", result["title"])
-	d.Set("description", result["description"])
-	d.Set("status", result["status"])
-	d.Set("priority", result["priority"])
-	d.Set("creator_id", result["creator_id"])
-	d.Set("team_id", result["team_id"])
-	d.Set("assignees", result["assignees"])
-	d.Set("parent_task_id", result["parent_task_id"])
-	d.Set("subtasks", result["subtasks"])
-	d.Set("labels", result["labels"])
-	d.Set("comments", result["comments"])
-	d.Set("attachments", result["attachments"])
+	teamID := strconv.Itoa(d.Get("team_id").(int))
+	team, err := getTeam(client, teamID)
+	if err != nil {
+		return "", err
+	}
 
-	return nil
+	var tasks []map[string]interface{}
+	if tasksRaw, ok := team["tasks"].([]interface{}); ok {
+		for _, task := range tasksRaw {
+			if taskMap, ok := task.(map[string]interface{}); ok {
+				tasks = append(tasks, taskMap)
+			}
+		}
+	}
+
+	title := d.Get("title").(string)
+	return findOne(tasks, "task", fmt.Sprintf("title %q in team %s", title, teamID), func(task map[string]interface{}) bool {
+		return task["title"] == title
+	})
 }
@@ -0,0 +1,400 @@
+package taskmanager
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataTasks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadTasks,
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"priority": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"assignee_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"creator_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"label_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"parent_task_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"top_level_only"},
+			},
+			"top_level_only": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"parent_task_id"},
+			},
+			"due_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDueDate,
+			},
+			"due_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDueDate,
+			},
+			"overdue": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"done_statuses": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"title_contains": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"sort_by": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "id",
+				ValidateFunc: validation.StringInSlice([]string{"id", "title", "status", "priority", "due_date"}, false),
+			},
+			"sort_order": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "asc",
+				ValidateFunc: validation.StringInSlice([]string{"asc", "desc"}, false),
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"tasks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"priority": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"due_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"team_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"creator_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"parent_task_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"assignees": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+						"labels": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadTasks(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	tasks, err := listObjects(client, "api/tasks", "tasks")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filters, err := taskFilters(d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matched []map[string]interface{}
+	for _, task := range tasks {
+		if matchesAll(task, filters) {
+			matched = append(matched, task)
+		}
+	}
+
+	sortTasks(matched, d.Get("sort_by").(string), d.Get("sort_order").(string) == "desc", client.allowedPriorities)
+
+	if limit := d.Get("limit").(int); limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	ids := make([]int, 0, len(matched))
+	items := make([]interface{}, 0, len(matched))
+	for _, task := range matched {
+		item := flattenTaskSummary(task)
+		item["description"] = stringValue(task["description"])
+		item["creator_id"] = intValue(task["creator_id"])
+		item["parent_task_id"] = intValue(task["parent_task_id"])
+		item["assignees"] = flattenIDs(task["assignees"])
+		item["labels"] = flattenIDs(task["labels"])
+		item["created_at"] = formatTimestamp(task, createdAtKeys...)
+		item["updated_at"] = formatTimestamp(task, updatedAtKeys...)
+
+		ids = append(ids, item["id"].(int))
+		items = append(items, item)
+	}
+
+	if err := d.Set("tasks", items); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("ids", ids)
+	d.SetId(listID(ids))
+
+	return nil
+}
+
+// taskFilters builds a predicate for each filter argument that is set.
+func taskFilters(d *schema.ResourceData, client *TaskManagerClient) ([]func(map[string]interface{}) bool, error) {
+	var filters []func(map[string]interface{}) bool
+
+	for _, key := range []string{"team_id", "creator_id", "parent_task_id"} {
+		if v, ok := d.GetOk(key); ok {
+			want := v.(int)
+			filters = append(filters, func(task map[string]interface{}) bool {
+				return intValue(task[key]) == want
+			})
+		}
+	}
+
+	if v, ok := d.GetOk("top_level_only"); ok && v.(bool) {
+		filters = append(filters, func(task map[string]interface{}) bool {
+			return intValue(task["parent_task_id"]) == 0
+		})
+	}
+
+	for _, key := range []string{"status", "priority"} {
+		if v, ok := d.GetOk(key); ok {
+			want := v.(string)
+			filters = append(filters, func(task map[string]interface{}) bool {
+				return strings.EqualFold(stringValue(task[key]), want)
+			})
+		}
+	}
+
+	if v, ok := d.GetOk("assignee_id"); ok {
+		filters = append(filters, func(task map[string]interface{}) bool {
+			return slices.Contains(flattenIDs(task["assignees"]), v.(int))
+		})
+	}
+	if v, ok := d.GetOk("label_id"); ok {
+		filters = append(filters, func(task map[string]interface{}) bool {
+			return slices.Contains(flattenIDs(task["labels"]), v.(int))
+		})
+	}
+
+	if v, ok := d.GetOk("title_contains"); ok {
+		want := strings.ToLower(v.(string))
+		filters = append(filters, func(task map[string]interface{}) bool {
+			return strings.Contains(strings.ToLower(stringValue(task["title"])), want)
+		})
+	}
+
+	if v, ok := d.GetOk("due_before"); ok {
+		before, err := parseDueDate(v.(string), client.defaultLocation)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, func(task map[string]interface{}) bool {
+			due, ok := taskDueDate(task)
+			return ok && due.Before(before)
+		})
+	}
+	if v, ok := d.GetOk("due_after"); ok {
+		after, err := parseDueDate(v.(string), client.defaultLocation)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, func(task map[string]interface{}) bool {
+			due, ok := taskDueDate(task)
+			return ok && due.After(after)
+		})
+	}
+
+	if v, ok := d.GetOk("overdue"); ok && v.(bool) {
+		now := time.Now()
+		doneStatuses := stringListOrDefault(d.Get("done_statuses").([]interface{}), nil)
+		filters = append(filters, func(task map[string]interface{}) bool {
+			due, ok := taskDueDate(task)
+			if !ok || !due.Before(now) {
+				return false
+			}
+			done := doneStatuses
+			if len(done) == 0 {
+				done = []string{client.doneStatus(intValue(task["team_id"]))}
+			}
+			return !slices.ContainsFunc(done, func(s string) bool {
+				return strings.EqualFold(stringValue(task["status"]), s)
+			})
+		})
+	}
+
+	return filters, nil
+}
+
+func matchesAll(object map[string]interface{}, filters []func(map[string]interface{}) bool) bool {
+	for _, filter := range filters {
+		if !filter(object) {
+			return false
+		}
+	}
+	return true
+}
+
+func taskDueDate(task map[string]interface{}) (time.Time, bool) {
+	raw := flattenDueDate(task["due_date"], "", nil)
+	if raw == "" {
+		return time.Time{}, false
+	}
+
+	due, err := time.Parse(time.RFC3339, raw)
+	return due, err == nil
+}
+
+// sortTasks orders tasks by the given field, falling back to the ID. Tasks
+// are ordered by priority in the order of the provider's allowed_priorities.
+// Tasks without a due date, or with a priority that is not allowed, come
+// last in either order.
+func sortTasks(tasks []map[string]interface{}, sortBy string, desc bool, priorities []string) {
+	// missing reports whether the task has no value to sort by.
+	missing := func(task map[string]interface{}) bool {
+		switch sortBy {
+		case "priority":
+			return priorityRank(stringValue(task["priority"]), priorities) == len(priorities)
+		case "due_date":
+			_, ok := taskDueDate(task)
+			return !ok
+		}
+		return false
+	}
+
+	compare := func(a, b map[string]interface{}) int {
+		switch sortBy {
+		case "title", "status":
+			return strings.Compare(strings.ToLower(stringValue(a[sortBy])), strings.ToLower(stringValue(b[sortBy])))
+		case "priority":
+			return priorityRank(stringValue(a["priority"]), priorities) - priorityRank(stringValue(b["priority"]), priorities)
+		case "due_date":
+			aDue, _ := taskDueDate(a)
+			bDue, _ := taskDueDate(b)
+			return aDue.Compare(bDue)
+		}
+		return 0
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		aMissing, bMissing := missing(tasks[i]), missing(tasks[j])
+		if aMissing != bMissing {
+			return bMissing
+		}
+
+		c := 0
+		if !aMissing {
+			c = compare(tasks[i], tasks[j])
+		}
+		if c == 0 {
+			aID, _ := objectID(tasks[i])
+			bID, _ := objectID(tasks[j])
+			c = aID - bID
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func priorityRank(priority string, priorities []string) int {
+	for i, p := range priorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+	return len(priorities)
+}
+
+// listID derives a stable ID for a list data source from the IDs it found.
+func listID(ids []int) string {
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.Itoa(id)
+	}
+	return strconv.Itoa(schema.HashString(strings.Join(idStrs, ",")))
+}
@@ -12,13 +12,16 @@ import (
 func dataTeam() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataReadTeam,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
 			},
 			"name": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"description": {
@@ -43,58 +46,49 @@ func dataTeam() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+			"member_details": userSummarySchema(),
+			"task_details":   taskSummarySchema(),
+		}),
 	}
 }
 
 func dataReadTeam(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	idInt := d.Get("id").(int)
-	idStr := strconv.Itoa(idInt)
+	idStr, err := lookupTeamID(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	var outer map[string]interface{}
-	if err := client.Get("api/teams/"+idStr, &outer); err != nil {
+	result, err := getTeam(client, idStr)
+	if err != nil {
 		return diag.FromErr(err)
 	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("team %s has been deleted", idStr)
+	}
 
 	d.SetId(idStr)
 
-	result, ok := outer["team"].(map[string]interface{})
-	if !ok {
-		return diag.FromErr(fmt.Errorf("unable to get team"))
-	}
-
-	d.Set("name", result["name"])
-	d.Set("description", result["description"])
+	flattenTeam(d, result)
+	flattenTeamDetails(d, result)
 
-	if ownerID, ok := result["owner_id"].(float64); ok {
-		d.Set("owner_id", int(ownerID))
-	}
+	return nil
+}
 
-	var memberIDs []int
-	if membersRaw, ok := result["members"].([]interface{}); ok {
-		for _, member := range membersRaw {
-			if memberMap, ok := member.(map[string]interface{}); ok {
-				if idFloat, ok := memberMap["ID"].(float64); ok {
-					memberIDs = append(memberIDs, int(idFloat))
-				}
-			}
-		}
+// lookupTeamID returns the ID of the team selected by id or name.
+func lookupTeamID(client *TaskManagerClient, d *schema.ResourceData) (string, error) {
+	if id, ok := d.GetOk("id"); ok {
+		return strconv.Itoa(id.(int)), nil
 	}
-	d.Set("members", memberIDs)
 
-	var taskIDs []int
-	if tasksRaw, ok := result["tasks"].([]interface{}); ok {
-		for _, task := range tasksRaw {
-			if taskMap, ok := task.(map[string]interface{}); ok {
-				if idFloat, ok := taskMap["id"].(float64); ok {
-					taskIDs = append(taskIDs, int(idFloat))
-				}
-			}
-		}
+	teams, err := listObjects(client, "api/teams", "teams")
+	if err != nil {
+		return "", err
 	}
-	d.Set("tasks", taskIDs)
 
-	return nil
+	name := d.Get("name").(string)
+	return findOne(teams, "team", fmt.Sprintf("name %q", name), func(team map[string]interface{}) bool {
+		return team["name"] == name
+	})
 }
@@ -0,0 +1,128 @@
+package taskmanager
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataTeams() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadTeams,
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"member_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"owner_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"teams": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"members": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadTeams(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	teams, err := listObjects(client, "api/teams", "teams")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var filters []func(map[string]interface{}) bool
+
+	if v, ok := d.GetOk("name_prefix"); ok {
+		prefix := strings.ToLower(v.(string))
+		filters = append(filters, func(team map[string]interface{}) bool {
+			return strings.HasPrefix(strings.ToLower(stringValue(team["name"])), prefix)
+		})
+	}
+	if v, ok := d.GetOk("member_id"); ok {
+		filters = append(filters, func(team map[string]interface{}) bool {
+			return slices.Contains(flattenIDs(team["members"]), v.(int))
+		})
+	}
+	if v, ok := d.GetOk("owner_id"); ok {
+		filters = append(filters, func(team map[string]interface{}) bool {
+			return intValue(team["owner_id"]) == v.(int)
+		})
+	}
+
+	ids := []int{}
+	items := []interface{}{}
+	for _, team := range sortByID(teams) {
+		if !matchesAll(team, filters) {
+			continue
+		}
+
+		item := flattenTeamSummary(team)
+		item["members"] = flattenIDs(team["members"])
+		item["created_at"] = formatTimestamp(team, createdAtKeys...)
+		item["updated_at"] = formatTimestamp(team, updatedAtKeys...)
+
+		ids = append(ids, item["id"].(int))
+		items = append(items, item)
+	}
+
+	if err := d.Set("teams", items); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("ids", ids)
+	d.SetId(listID(ids))
+
+	return nil
+}
@@ -2,7 +2,9 @@ package taskmanager
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -11,13 +13,16 @@ import (
 func dataUser() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataReadUser,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "uname", "email"},
 			},
 			"uname": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"name": {
@@ -26,12 +31,14 @@ func dataUser() *schema.Resource {
 			},
 			"email": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"password": {
-				Type:      schema.TypeString,
-				Computed:  true,
-				Sensitive: true,
+				Type:       schema.TypeString,
+				Computed:   true,
+				Sensitive:  true,
+				Deprecated: "Password hashes are no longer read into state, this attribute is always empty.",
 			},
 			"role": {
 				Type:     schema.TypeString,
@@ -79,102 +86,56 @@ func dataUser() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+			"team_details":           teamSummarySchema(),
+			"tasks_created_details":  taskSummarySchema(),
+			"tasks_assigned_details": taskSummarySchema(),
+		}),
 	}
 }
 
 func dataReadUser(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	idInt := d.Get("id").(int)
-	idStr := strconv.Itoa(idInt)
+	idStr, err := lookupUserID(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	user := make(map[string]interface{})
-	if err := client.Get("api/users/"+idStr, &user); err != nil {
+	result, err := getUser(client, idStr)
+	if err != nil {
 		return diag.FromErr(err)
 	}
+	if isSoftDeleted(result) {
+		return diag.Errorf("user %s has been deleted", idStr)
+	}
 
 	d.SetId(idStr)
 
-	result := user["user"].(map[string]interface{})
-
-	d.Set("uname", result["uname"])
-	d.Set("name", result["name"])
-	d.Set("email", result["email"])
-	d.Set("password", result["password"])
-	d.Set("role", result["role"])
-
-	var teamIds []int
-	if teamsRaw, ok := result["teams"].([]interface{}); ok {
-		for _, team := range teamsRaw {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamIds = append(teamIds, int(teamId))
-				}
-			}
-		}
-	}
-	d.Set("teams", teamIds)
-
-	var teamCreatedIds []int
-	if teamsRaw, ok := result["tasks_created"].([]interface{}); ok {
-		for _, team := range teamsRaw {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamCreatedIds = append(teamCreatedIds, int(teamId))
-				}
-			}
-		}
-	}
-	d.Set("tasks_created", teamCreatedIds)
+	flattenUser(d, result)
+	flattenUserDetails(d, result)
 
-	var teamAssignedIds []int
-	if teamsRaw, ok := result["tasks_assigned"].([]interface{}); ok {
-		for _, team := range teamsRaw {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamAssignedIds = append(teamAssignedIds, int(teamId))
-				}
-			}
-		}
-	}
-	d.Set("tasks_assigned", teamAssignedIds)
+	return nil
+}
 
-	var commentIds []int
-	if commentsRaw, ok := result["comments"].([]interface{}); ok {
-		for _, comment := range commentsRaw {
-			if commentMap, ok := comment.(map[string]interface{}); ok {
-				if commentId, ok := commentMap["ID"].(float64); ok {
-					commentIds = append(commentIds, int(commentId))
-				}
-			}
-		}
+// lookupUserID returns the ID of the user selected by id, uname or email.
+func lookupUserID(client *TaskManagerClient, d *schema.ResourceData) (string, error) {
+	if id, ok := d.GetOk("id"); ok {
+		return strconv.Itoa(id.(int)), nil
 	}
-	d.Set("comments", commentIds)
 
-	var attachmentIds []int
-	if attachmentRaw, ok := result["attachments"].([]interface{}); ok {
-		for _, attachment := range attachmentRaw {
-			if attachmentMap, ok := attachment.(map[string]interface{}); ok {
-				if attachmentId, ok := attachmentMap["ID"].(float64); ok {
-					attachmentIds = append(attachmentIds, int(attachmentId))
-				}
-			}
-		}
+	users, err := listObjects(client, "api/users", "users")
+	if err != nil {
+		return "", err
 	}
-	d.Set("attachments", attachmentIds)
 
-	var notificationIds []int
-	if notificationsRaw, ok := result["notifications"].([]interface{}); ok {
-		for _, notification := range notificationsRaw {
-			if notificationMap, ok := notification.(map[string]interface{}); ok {
-				if notificationId, ok := notificationMap["ID"].(float64); ok {
-					notificationIds = append(notificationIds, int(notificationId))
-				}
-			}
-		}
+	if uname, ok := d.GetOk("uname"); ok {
+		return findOne(users, "user", fmt.Sprintf("uname %q", uname), func(user map[string]interface{}) bool {
+			return user["uname"] == uname.(string)
+		})
 	}
-	d.Set("notifications", notificationIds)
 
-	return nil
+	email := d.Get("email").(string)
+	return findOne(users, "user", fmt.Sprintf("email %q", email), func(user map[string]interface{}) bool {
+		return strings.EqualFold(stringValue(user["email"]), email)
+	})
 }
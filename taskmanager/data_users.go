@@ -0,0 +1,161 @@
+package taskmanager
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataUsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataReadUsers,
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"team_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"has_no_team"},
+			},
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"has_no_team": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"uname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"teams": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadUsers(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	users, err := listObjects(client, "api/users", "users")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The user list does not include each user's teams, but every team
+	// lists its members.
+	teams, err := listObjects(client, "api/teams", "teams")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userTeams := map[int][]int{}
+	for _, team := range sortByID(teams) {
+		teamID, _ := objectID(team)
+		for _, member := range flattenIDs(team["members"]) {
+			userTeams[member] = append(userTeams[member], teamID)
+		}
+	}
+	teamsOf := func(user map[string]interface{}) []int {
+		id, _ := objectID(user)
+		return userTeams[id]
+	}
+
+	var filters []func(map[string]interface{}) bool
+
+	if v, ok := d.GetOk("role"); ok {
+		filters = append(filters, func(user map[string]interface{}) bool {
+			return strings.EqualFold(stringValue(user["role"]), v.(string))
+		})
+	}
+	if v, ok := d.GetOk("name_prefix"); ok {
+		prefix := strings.ToLower(v.(string))
+		filters = append(filters, func(user map[string]interface{}) bool {
+			return strings.HasPrefix(strings.ToLower(stringValue(user["uname"])), prefix) ||
+				strings.HasPrefix(strings.ToLower(stringValue(user["name"])), prefix)
+		})
+	}
+	if v, ok := d.GetOk("team_id"); ok {
+		filters = append(filters, func(user map[string]interface{}) bool {
+			return slices.Contains(teamsOf(user), v.(int))
+		})
+	}
+	if v, ok := d.GetOk("has_no_team"); ok && v.(bool) {
+		filters = append(filters, func(user map[string]interface{}) bool {
+			return len(teamsOf(user)) == 0
+		})
+	}
+
+	ids := []int{}
+	items := []interface{}{}
+	for _, user := range sortByID(users) {
+		if !matchesAll(user, filters) {
+			continue
+		}
+
+		item := flattenUserSummary(user)
+		item["teams"] = teamsOf(user)
+		item["created_at"] = formatTimestamp(user, createdAtKeys...)
+		item["updated_at"] = formatTimestamp(user, updatedAtKeys...)
+
+		ids = append(ids, item["id"].(int))
+		items = append(items, item)
+	}
+
+	if err := d.Set("users", items); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("ids", ids)
+	d.SetId(listID(ids))
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package taskmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dateOnlyLayout is accepted for due dates in addition to RFC 3339. Such
+// dates are taken as midnight in the provider's default_timezone.
+const dateOnlyLayout = "2006-01-02"
+
+func parseDueDate(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(dateOnlyLayout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp (2025-06-25T18:30:00Z) nor a date (2025-06-25)", value)
+	}
+
+	return t, nil
+}
+
+// normalizeDueDate converts a configured due date into the RFC 3339 form
+// expected by the API.
+func normalizeDueDate(value string, location *time.Location) (string, error) {
+	t, err := parseDueDate(value, location)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Format(time.RFC3339), nil
+}
+
+func validateDueDate(v interface{}, k string) ([]string, []error) {
+	if _, err := parseDueDate(v.(string), time.UTC); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentDueDate hides diffs between timestamps that denote the
+// same instant, such as 2025-06-25T18:30:00Z and 2025-06-26T00:00:00+05:30.
+func suppressEquivalentDueDate(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+
+	return oldTime.Equal(newTime)
+}
+
+// flattenDueDate returns the due date to store in state. The value already in
+// state is kept when it denotes the same instant as the API value, so a
+// date-only or offset-shifted configuration does not show up as drift.
+func flattenDueDate(apiValue interface{}, current string, location *time.Location) string {
+	raw, ok := apiValue.(string)
+	if !ok || raw == "" {
+		return ""
+	}
+
+	apiTime, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return raw
+	}
+	if apiTime.IsZero() {
+		return ""
+	}
+
+	if current != "" {
+		if currentTime, err := parseDueDate(current, location); err == nil && currentTime.Equal(apiTime) {
+			return current
+		}
+	}
+
+	return raw
+}
@@ -0,0 +1,376 @@
+package taskmanager
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// encryptedSuffix is added to the file name of encrypted attachments.
+const encryptedSuffix = ".enc"
+
+// ageMagic starts every file in the age format.
+const ageMagic = "age-encryption.org/v1\n"
+
+// aesGCMMagic starts every file encrypted with an AES key file. It is
+// followed by a random salt and the content in chunks of aesGCMChunkSize
+// bytes. As in age, each file is sealed with its own key, derived from the
+// key file and the salt with HKDF-SHA256, so nonces only need to be unique
+// within the file. Each chunk is sealed with AES-256-GCM under a nonce made
+// of the chunk's index and a flag marking the last chunk, so chunks cannot be
+// reordered, dropped or truncated unnoticed.
+const aesGCMMagic = "taskmanager-aes-gcm-v1\n"
+
+const (
+	aesGCMChunkSize = 64 * 1024
+	aesGCMSaltSize  = 32
+	aesGCMKeyInfo   = "taskmanager attachment payload"
+)
+
+// attachmentEncryption encrypts attachments before they are uploaded, either
+// to age recipients or with a symmetric AES key.
+type attachmentEncryption struct {
+	recipients []age.Recipient
+	identities []age.Identity
+	aesKey     []byte
+
+	// fingerprint identifies the key without revealing it, so a key change
+	// can be planned.
+	fingerprint string
+}
+
+func attachmentEncryptionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"age_recipients": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"age_identity_file": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"aes_key_file": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func expandAttachmentEncryption(raw []interface{}) (*attachmentEncryption, error) {
+	if len(raw) == 0 || raw[0] == nil {
+		return nil, nil
+	}
+	block := raw[0].(map[string]interface{})
+
+	enc := &attachmentEncryption{}
+	recipients := stringListOrDefault(block["age_recipients"].([]interface{}), nil)
+	identityFile := block["age_identity_file"].(string)
+	keyFile := block["aes_key_file"].(string)
+
+	switch {
+	case keyFile != "" && (len(recipients) > 0 || identityFile != ""):
+		return nil, fmt.Errorf("attachment_encryption: aes_key_file cannot be combined with age_recipients or age_identity_file")
+	case keyFile != "":
+		key, err := readAESKey(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("attachment_encryption.aes_key_file: %w", err)
+		}
+		enc.aesKey = key
+		sum := sha256.Sum256(append([]byte("taskmanager attachment key\n"), key...))
+		enc.fingerprint = "aes:" + hex.EncodeToString(sum[:8])
+		return enc, nil
+	case len(recipients) == 0 && identityFile == "":
+		return nil, fmt.Errorf("attachment_encryption: set age_recipients or aes_key_file")
+	}
+
+	for _, r := range recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("attachment_encryption.age_recipients: %w", err)
+		}
+		enc.recipients = append(enc.recipients, recipient)
+	}
+
+	if identityFile != "" {
+		file, err := os.Open(identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("attachment_encryption.age_identity_file: %w", err)
+		}
+		defer file.Close()
+
+		if enc.identities, err = age.ParseIdentities(file); err != nil {
+			return nil, fmt.Errorf("attachment_encryption.age_identity_file: %w", err)
+		}
+	}
+
+	sorted := append([]string{}, recipients...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	enc.fingerprint = "age:" + hex.EncodeToString(sum[:8])
+
+	return enc, nil
+}
+
+// readAESKey reads a 256-bit key stored raw, hex encoded or base64 encoded.
+func readAESKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+
+	text := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("the file must hold a 32 byte key, raw, hex or base64 encoded")
+}
+
+// canEncrypt reports whether content can be encrypted. A provider with only
+// an age identity can decrypt but not encrypt.
+func (e *attachmentEncryption) canEncrypt() bool {
+	return e != nil && (e.aesKey != nil || len(e.recipients) > 0)
+}
+
+// encryptBody encrypts body into a temporary file, which is removed when the
+// returned body is closed. Encrypting first gives the upload a known length.
+func (e *attachmentEncryption) encryptBody(body *attachmentBody) (*attachmentBody, error) {
+	tmp, err := os.CreateTemp("", "taskmanager-attachment-*"+encryptedSuffix)
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error {
+		tmp.Close()
+		return os.Remove(tmp.Name())
+	}
+
+	if err := e.encrypt(tmp, body); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("unable to encrypt the attachment: %w", err)
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return &attachmentBody{ReadSeeker: tmp, size: size, close: cleanup}, nil
+}
+
+// encrypt writes the encrypted content of src to dst.
+func (e *attachmentEncryption) encrypt(dst io.Writer, src io.Reader) error {
+	if e.aesKey == nil {
+		w, err := age.Encrypt(dst, e.recipients...)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+
+	salt := make([]byte, aesGCMSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := newAESGCM(e.aesKey, salt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(dst, aesGCMMagic); err != nil {
+		return err
+	}
+	if _, err := dst.Write(salt); err != nil {
+		return err
+	}
+
+	// Reading one byte ahead tells whether a chunk is the last one.
+	chunk := make([]byte, aesGCMChunkSize+1)
+	n, err := io.ReadFull(src, chunk)
+	for index := uint32(0); ; index++ {
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := n <= aesGCMChunkSize
+		plain := chunk[:min(n, aesGCMChunkSize)]
+
+		if _, err := dst.Write(aead.Seal(nil, chunkNonce(index, last), plain, nil)); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+
+		chunk[0] = chunk[aesGCMChunkSize]
+		var m int
+		m, err = io.ReadFull(src, chunk[1:])
+		n = m + 1
+	}
+}
+
+// decrypt returns a reader for the decrypted content of r, and whether r was
+// encrypted at all. Content that is not encrypted is returned as it is.
+func (e *attachmentEncryption) decrypt(r *bufio.Reader) (io.Reader, bool, error) {
+	if head, _ := r.Peek(len(ageMagic)); string(head) == ageMagic {
+		if e == nil || len(e.identities) == 0 {
+			return nil, true, fmt.Errorf("the attachment is encrypted with age, set attachment_encryption.age_identity_file to decrypt it")
+		}
+		plain, err := age.Decrypt(r, e.identities...)
+		return plain, true, err
+	}
+
+	if head, _ := r.Peek(len(aesGCMMagic)); string(head) == aesGCMMagic {
+		if e == nil || e.aesKey == nil {
+			return nil, true, fmt.Errorf("the attachment is encrypted with an AES key, set attachment_encryption.aes_key_file to decrypt it")
+		}
+		plain, err := e.aesGCMReader(r)
+		return plain, true, err
+	}
+
+	return r, false, nil
+}
+
+// isEncrypted reports whether r starts with encrypted content, without
+// consuming it.
+func isEncrypted(r *bufio.Reader) bool {
+	for _, magic := range []string{ageMagic, aesGCMMagic} {
+		if head, _ := r.Peek(len(magic)); string(head) == magic {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *attachmentEncryption) aesGCMReader(r io.Reader) (io.Reader, error) {
+	header := make([]byte, len(aesGCMMagic)+aesGCMSaltSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.New("unable to decrypt the attachment: it is damaged")
+	}
+
+	aead, err := newAESGCM(e.aesKey, header[len(aesGCMMagic):])
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesGCMReader{
+		aead:  aead,
+		src:   r,
+		chunk: make([]byte, aesGCMChunkSize+aead.Overhead()+1),
+	}, nil
+}
+
+// aesGCMReader decrypts the chunks written by attachmentEncryption.encrypt.
+type aesGCMReader struct {
+	aead  cipher.AEAD
+	src   io.Reader
+	index uint32
+
+	// chunk holds a sealed chunk and the first byte of the next one.
+	chunk    []byte
+	buffered int
+	plain    []byte
+	done     bool
+}
+
+func (r *aesGCMReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+func (r *aesGCMReader) next() error {
+	n, err := io.ReadFull(r.src, r.chunk[r.buffered:])
+	n += r.buffered
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	sealedSize := aesGCMChunkSize + r.aead.Overhead()
+	last := n <= sealedSize
+	sealed := r.chunk[:min(n, sealedSize)]
+
+	plain, err := r.aead.Open(nil, chunkNonce(r.index, last), sealed, nil)
+	if err != nil {
+		return errors.New("unable to decrypt the attachment: it is damaged or was encrypted with another key")
+	}
+
+	r.plain = plain
+	r.index++
+	r.done = last
+	if !last {
+		r.chunk[0] = r.chunk[sealedSize]
+		r.buffered = 1
+	}
+	return nil
+}
+
+// newAESGCM returns the cipher for one file, keyed with a key derived from
+// the key file's key and the file's salt.
+func newAESGCM(key []byte, salt []byte) (cipher.AEAD, error) {
+	fileKey, err := hkdf.Key(sha256.New, key, salt, aesGCMKeyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the 12 byte nonce of a chunk: its index, big endian in
+// the first 11 bytes, and 1 for the last chunk or 0 otherwise.
+func chunkNonce(index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint32(nonce[7:11], index)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
@@ -0,0 +1,130 @@
+package taskmanager
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func testAESEncryption(t *testing.T) *attachmentEncryption {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return &attachmentEncryption{aesKey: key}
+}
+
+func testEncrypt(t *testing.T, enc *attachmentEncryption, plain []byte) []byte {
+	var sealed bytes.Buffer
+	if err := enc.encrypt(&sealed, bytes.NewReader(plain)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return sealed.Bytes()
+}
+
+func testDecrypt(enc *attachmentEncryption, sealed []byte) ([]byte, error) {
+	plain, encrypted, err := enc.decrypt(bufio.NewReader(bytes.NewReader(sealed)))
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return io.ReadAll(plain)
+}
+
+func TestAESEncryptionRoundTrip(t *testing.T) {
+	enc := testAESEncryption(t)
+
+	for _, size := range []int{0, 1, aesGCMChunkSize - 1, aesGCMChunkSize, aesGCMChunkSize + 1, 3*aesGCMChunkSize + 17} {
+		plain := make([]byte, size)
+		if _, err := rand.Read(plain); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		sealed := testEncrypt(t, enc, plain)
+		if !isEncrypted(bufio.NewReader(bytes.NewReader(sealed))) {
+			t.Fatalf("size %d: encrypted content not detected", size)
+		}
+
+		got, err := testDecrypt(enc, sealed)
+		if err != nil {
+			t.Fatalf("size %d: err: %s", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Fatalf("size %d: decrypted content differs", size)
+		}
+	}
+}
+
+func TestAESEncryptionUniquePerFile(t *testing.T) {
+	enc := testAESEncryption(t)
+	plain := []byte("same content")
+
+	first := testEncrypt(t, enc, plain)
+	second := testEncrypt(t, enc, plain)
+	if bytes.Equal(first, second) {
+		t.Fatal("encrypting the same content twice gave the same bytes")
+	}
+}
+
+func TestAESEncryptionTruncated(t *testing.T) {
+	enc := testAESEncryption(t)
+	plain := make([]byte, 2*aesGCMChunkSize+100)
+	sealed := testEncrypt(t, enc, plain)
+
+	header := len(aesGCMMagic) + aesGCMSaltSize
+	sealedChunk := aesGCMChunkSize + 16
+	for _, size := range []int{
+		len(aesGCMMagic) + 5,
+		header,
+		header + sealedChunk,
+		header + 2*sealedChunk,
+		len(sealed) - 1,
+	} {
+		if _, err := testDecrypt(enc, sealed[:size]); err == nil {
+			t.Fatalf("truncated to %d bytes: expected an error", size)
+		}
+	}
+}
+
+func TestAESEncryptionFlippedByte(t *testing.T) {
+	enc := testAESEncryption(t)
+	sealed := testEncrypt(t, enc, []byte("the quarterly report"))
+
+	for _, i := range []int{len(aesGCMMagic), len(aesGCMMagic) + aesGCMSaltSize, len(sealed) - 1} {
+		damaged := append([]byte{}, sealed...)
+		damaged[i] ^= 0x01
+		if _, err := testDecrypt(enc, damaged); err == nil {
+			t.Fatalf("byte %d flipped: expected an error", i)
+		}
+	}
+}
+
+func TestAESEncryptionWrongKey(t *testing.T) {
+	sealed := testEncrypt(t, testAESEncryption(t), []byte("the quarterly report"))
+
+	if _, err := testDecrypt(testAESEncryption(t), sealed); err == nil {
+		t.Fatal("expected an error decrypting with another key")
+	}
+
+	var noKey *attachmentEncryption
+	if _, _, err := noKey.decrypt(bufio.NewReader(bytes.NewReader(sealed))); err == nil {
+		t.Fatal("expected an error decrypting without a key")
+	}
+}
+
+func TestDecryptPlainContent(t *testing.T) {
+	plain, encrypted, err := testAESEncryption(t).decrypt(bufio.NewReader(bytes.NewReader([]byte("not encrypted"))))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if encrypted {
+		t.Fatal("plain content reported as encrypted")
+	}
+	if got, _ := io.ReadAll(plain); string(got) != "not encrypted" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package taskmanager
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The flatten functions copy an API object into a resource or data source.
+// Resources and data sources of the same object type share them, so both
+// expose the same attributes and relationship data.
+
+func flattenUser(d *schema.ResourceData, user map[string]interface{}) {
+	d.Set("uname", user["uname"])
+	d.Set("name", user["name"])
+	d.Set("email", user["email"])
+	d.Set("role", user["role"])
+	d.Set("teams", flattenIDs(user["teams"]))
+	d.Set("tasks_created", flattenIDs(user["tasks_created"]))
+	d.Set("tasks_assigned", flattenIDs(user["tasks_assigned"]))
+	d.Set("comments", flattenIDs(user["comments"]))
+	d.Set("attachments", flattenIDs(user["attachments"]))
+	d.Set("notifications", flattenIDs(user["notifications"]))
+	flattenTimestamps(d, user)
+}
+
+func flattenTeam(d *schema.ResourceData, team map[string]interface{}) {
+	d.Set("name", team["name"])
+	d.Set("description", team["description"])
+	if ownerID, ok := team["owner_id"].(float64); ok {
+		d.Set("owner_id", int(ownerID))
+	}
+	d.Set("members", flattenIDs(team["members"]))
+	d.Set("tasks", flattenIDs(team["tasks"]))
+	flattenTimestamps(d, team)
+}
+
+func flattenLabel(d *schema.ResourceData, label map[string]interface{}) {
+	d.Set("name", label["name"])
+	d.Set("color", label["color"])
+	d.Set("description", label["description"])
+	d.Set("team_id", intValue(label["team_id"]))
+	flattenTimestamps(d, label)
+}
+
+func flattenTask(d *schema.ResourceData, task map[string]interface{}, client *TaskManagerClient) {
+	d.Set("title", task["title"])
+	d.Set("description", task["description"])
+	d.Set("status", task["status"])
+	d.Set("priority", task["priority"])
+	d.Set("due_date", flattenDueDate(task["due_date"], d.Get("due_date").(string), client.defaultLocation))
+	d.Set("creator_id", task["creator_id"])
+	d.Set("team_id", task["team_id"])
+	d.Set("parent_task_id", task["parent_task_id"])
+	d.Set("assignees", flattenIDs(task["assignees"]))
+	d.Set("subtasks", flattenIDs(task["subtasks"]))
+	d.Set("labels", flattenIDs(task["labels"]))
+	d.Set("comments", flattenIDs(task["comments"]))
+	d.Set("attachments", flattenIDs(task["attachments"]))
+	flattenTimestamps(d, task)
+}
+
+func flattenComment(d *schema.ResourceData, comment map[string]interface{}) {
+	d.Set("content", comment["content"])
+	d.Set("user_id", comment["user_id"])
+	d.Set("task_id", comment["task_id"])
+	d.Set("parent_comment_id", comment["parent_comment_id"])
+	d.Set("subcomments", flattenIDs(comment["subcomments"]))
+	flattenTimestamps(d, comment)
+}
+
+func flattenAttachment(d *schema.ResourceData, attachment map[string]interface{}) {
+	d.Set("file_name", attachment["file_name"])
+	d.Set("task_id", attachment["task_id"])
+	if uploaderID, ok := attachment["uploader_id"].(float64); ok {
+		d.Set("uploader_id", int(uploaderID))
+	}
+	flattenTimestamps(d, attachment)
+}
+
+// flattenIDs returns the sorted IDs of a list of related objects as decoded
+// from JSON. Objects without an ID are skipped.
+func flattenIDs(raw interface{}) []int {
+	objects, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var ids []int
+	for _, object := range objects {
+		if objectMap, ok := object.(map[string]interface{}); ok {
+			if id, ok := objectID(objectMap); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// objectID returns the ID of an API object. GORM models serialize it as
+// "ID", while a few endpoints use "id".
+func objectID(object map[string]interface{}) (int, bool) {
+	for _, key := range []string{"ID", "id"} {
+		if id, ok := object[key].(float64); ok {
+			return int(id), true
+		}
+	}
+	return 0, false
+}
+
+// intValue returns a JSON number as an int, or 0 when v is not a number.
+func intValue(v interface{}) int {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return 0
+}
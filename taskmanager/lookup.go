@@ -0,0 +1,96 @@
+package taskmanager
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// listPageSize is the number of objects requested per page from list
+// endpoints.
+const listPageSize = 100
+
+// listObjects returns all objects of a list endpoint, which the API wraps in
+// an object under key, e.g. {"users": [...]}. Pages are requested until one
+// comes back short. A backend that ignores the paging parameters returns the
+// same objects again, so a page without a single unseen ID also ends the
+// listing. Soft-deleted objects count as seen, so a page of them alone does
+// not.
+func listObjects(client *TaskManagerClient, endPoint string, key string) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+	seen := map[int]bool{}
+
+	for page := 1; ; page++ {
+		var outer map[string]interface{}
+		if err := client.Get(fmt.Sprintf("%s?page=%d&limit=%d", endPoint, page, listPageSize), &outer); err != nil {
+			return nil, err
+		}
+
+		raw, ok := outer[key].([]interface{})
+		if !ok && outer[key] != nil {
+			return nil, fmt.Errorf("unable to list %s", key)
+		}
+
+		unseen := 0
+		for _, r := range raw {
+			object, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if id, ok := objectID(object); ok {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				unseen++
+			}
+			if !isSoftDeleted(object) {
+				objects = append(objects, object)
+			}
+		}
+
+		if len(raw) < listPageSize || unseen == 0 {
+			return objects, nil
+		}
+	}
+}
+
+// findOne returns the ID of the single object accepted by match. Finding no
+// object or more than one is an error, described using kind and criteria,
+// e.g. `user` and `uname "alice"`.
+func findOne(objects []map[string]interface{}, kind string, criteria string, match func(map[string]interface{}) bool) (string, error) {
+	var ids []int
+	for _, object := range objects {
+		if !match(object) {
+			continue
+		}
+		if id, ok := objectID(object); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s found with %s", kind, criteria)
+	case 1:
+		return strconv.Itoa(ids[0]), nil
+	}
+
+	sort.Ints(ids)
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.Itoa(id)
+	}
+	return "", fmt.Errorf("found %d %ss with %s (IDs %s), use id to select one", len(ids), kind, criteria, strings.Join(idStrs, ", "))
+}
+
+// sortByID orders listed objects by ID, so list data sources are stable.
+func sortByID(objects []map[string]interface{}) []map[string]interface{} {
+	sort.SliceStable(objects, func(i, j int) bool {
+		a, _ := objectID(objects[i])
+		b, _ := objectID(objects[j])
+		return a < b
+	})
+	return objects
+}
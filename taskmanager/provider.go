@@ -2,6 +2,7 @@ package taskmanager
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -21,20 +22,63 @@ func Provider(version string) *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("BASE_URL", nil),
 			},
+			"default_timezone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "UTC",
+			},
+			"allowed_statuses": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"allowed_priorities": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"allowed_roles": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"workflow":              workflowSchema(),
+			"attachment_policy":     attachmentPolicySchema(),
+			"attachment_encryption": attachmentEncryptionSchema(),
+			"upload_timeout": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultUploadTimeout.String(),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"taskmanager_user":       resourceUser(),
-			"taskmanager_team":       resourceTeam(),
-			"taskmanager_task":       resourceTask(),
-			"taskmanager_comment":    resourceComment(),
-			"taskmanager_attachment": resourceAttachment(),
+			"taskmanager_user":           resourceUser(),
+			"taskmanager_team":           resourceTeam(),
+			"taskmanager_task":           resourceTask(),
+			"taskmanager_comment":        resourceComment(),
+			"taskmanager_attachment":     resourceAttachment(),
+			"taskmanager_attachment_set": resourceAttachmentSet(),
+			"taskmanager_label":          resourceLabel(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"taskmanager_user":       dataUser(),
-			"taskmanager_team":       dataTeam(),
-			"taskmanager_task":       dataTask(),
-			"taskmanager_comment":    dataComment(),
-			"taskmanager_attachment": dataAttachment(),
+			"taskmanager_user":               dataUser(),
+			"taskmanager_current_user":       dataCurrentUser(),
+			"taskmanager_users":              dataUsers(),
+			"taskmanager_team":               dataTeam(),
+			"taskmanager_teams":              dataTeams(),
+			"taskmanager_task":               dataTask(),
+			"taskmanager_tasks":              dataTasks(),
+			"taskmanager_comment":            dataComment(),
+			"taskmanager_comments":           dataComments(),
+			"taskmanager_attachment":         dataAttachment(),
+			"taskmanager_attachment_content": dataAttachmentContent(),
+			"taskmanager_label":              dataLabel(),
 		},
 		ConfigureContextFunc: configureProviderClient,
 	}
@@ -50,5 +94,60 @@ func configureProviderClient(ctx context.Context, d *schema.ResourceData) (inter
 
 	client := NewClient(baseURL, token)
 
+	location, err := time.LoadLocation(d.Get("default_timezone").(string))
+	if err != nil {
+		return nil, diag.Errorf("invalid default_timezone: %s", err)
+	}
+	client.defaultLocation = location
+
+	uploadTimeout, err := time.ParseDuration(d.Get("upload_timeout").(string))
+	if err != nil || uploadTimeout < 0 {
+		return nil, diag.Errorf("invalid upload_timeout %q: use a duration such as \"30m\", or \"0\" for no limit", d.Get("upload_timeout").(string))
+	}
+	client.uploadTimeout = uploadTimeout
+
+	client.allowedStatuses = stringListOrDefault(d.Get("allowed_statuses").([]interface{}), defaultStatuses)
+	client.allowedPriorities = stringListOrDefault(d.Get("allowed_priorities").([]interface{}), defaultPriorities)
+	client.allowedRoles = stringListOrDefault(d.Get("allowed_roles").([]interface{}), defaultRoles)
+
+	workflows, err := expandWorkflows(d.Get("workflow").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.workflows = workflows
+
+	policy, err := expandAttachmentPolicy(d.Get("attachment_policy").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.attachmentPolicy = policy
+
+	encryption, err := expandAttachmentEncryption(d.Get("attachment_encryption").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.attachmentEncryption = encryption
+
+	// Statuses introduced by a workflow are valid task statuses as well.
+	for _, wf := range workflows {
+		for _, status := range wf.statuses {
+			if checkAllowedValue("status", status, client.allowedStatuses) != nil {
+				client.allowedStatuses = append(client.allowedStatuses, status)
+			}
+		}
+	}
+
 	return client, nil
 }
+
+func stringListOrDefault(raw []interface{}, defaults []string) []string {
+	if len(raw) == 0 {
+		return defaults
+	}
+
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		values = append(values, v.(string))
+	}
+	return values
+}
@@ -1,19 +1,24 @@
 package taskmanager
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
-	"mime/multipart"
-	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAttachment() *schema.Resource {
@@ -22,131 +27,646 @@ func resourceAttachment() *schema.Resource {
 		ReadContext:   resourceReadAttachment,
 		UpdateContext: resourceUpdateAttachment,
 		DeleteContext: resourceDeleteAttachment,
-		Schema: map[string]*schema.Schema{
+		CustomizeDiff: customdiff.All(attachmentContentDiff, planAttachmentEncryption, planAttachmentVersions),
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"file_name": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
-			"url": {
+			"content_type": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
+			},
+			"source_path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Deprecated:   "url is a local file path, use source_path instead",
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"content_base64": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringIsBase64,
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"source_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"source_dir": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: attachmentSourceKeys,
+			},
+			"archive_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "zip",
+				ValidateFunc: validation.StringInSlice(archiveFormats, false),
+			},
+			"source_sha256": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[0-9a-fA-F]{64}$`), "must be a hex encoded SHA-256"),
+				RequiredWith: []string{"source_url"},
 			},
 			"task_id": {
 				Type:     schema.TypeInt,
 				Required: true,
+				ForceNew: true,
 			},
 			"uploader_id": {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-		},
+			"content_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"encrypt": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"encryption_key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"keep_versions": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"attachment_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"version_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		}),
 	}
 }
 
 func resourceCreateAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*TaskManagerClient)
+	if diags := uploadAttachmentVersion(ctx, d, m, 1); diags.HasError() {
+		return diags
+	}
+	d.SetId(currentAttachmentID(d))
+	d.Set("version_ids", []string{d.Id()})
+
+	return resourceReadAttachment(ctx, d, m)
+}
 
-	taskID := d.Get("task_id").(int)
-	filePath := d.Get("url").(string)
+// uploadAttachmentVersion uploads the configured content as a new attachment
+// and makes it the resource's attachment_id. Versions after the first are
+// uploaded with the version in their file name, e.g. report.v2.pdf.
+func uploadAttachmentVersion(ctx context.Context, d *schema.ResourceData, m interface{}, version int) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
 
-	absPath, err := filepath.Abs(filepath.Clean(filePath))
+	src, err := expandAttachmentSource(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	file, err := os.Open(absPath)
+	fileName := d.Get("file_name").(string)
+	if fileName == "" {
+		fileName = src.fileName()
+	}
+
+	body, err := src.open(ctx, client)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer file.Close()
+	defer body.Close()
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	contentType := d.Get("content_type").(string)
+	if contentType == "" {
+		if contentType, err = body.contentType(fileName); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	part, err := writer.CreateFormFile("file", filepath.Base(absPath))
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create form part: %w", err))
+	// Content that was not known at plan time is only checked now, but
+	// still before it is uploaded.
+	if err := client.attachmentPolicy.check(fileName, contentType, body); err != nil {
+		return diag.Errorf("%s: %s", src.key, err)
 	}
-	if _, err := io.Copy(part, file); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to copy file: %w", err))
+
+	uploadName, uploadBody, uploadType := versionedFileName(fileName, version), body, contentType
+	fingerprint := ""
+	if d.Get("encrypt").(bool) {
+		enc := client.attachmentEncryption
+		if !enc.canEncrypt() {
+			return diag.Errorf("encrypt: the provider's attachment_encryption block needs age_recipients or aes_key_file")
+		}
+		if uploadBody, err = enc.encryptBody(body); err != nil {
+			return diag.FromErr(err)
+		}
+		defer uploadBody.Close()
+		uploadName += encryptedSuffix
+		uploadType = "application/octet-stream"
+		fingerprint = enc.fingerprint
 	}
-	writer.Close()
 
-	endpoint := fmt.Sprintf("api/tasks/%d/attachments", taskID)
-	req, err := http.NewRequest("POST", client.baseURL+endpoint, body)
+	id, err := uploadAttachment(ctx, client, d.Get("task_id").(int), uploadName, uploadType, uploadBody)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	req.Header.Set("Authorization", "Bearer "+client.token)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	d.Set("attachment_id", id)
+	d.Set("file_name", fileName)
+	d.Set("content_type", contentType)
+	d.Set("content_sha256", body.sha256)
+	d.Set("content_size", body.size)
+	d.Set("version", version)
+	d.Set("encryption_key_fingerprint", fingerprint)
 
-	resp, err := client.HTTPClient.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	return nil
+}
+
+// versionedFileName adds the version before the extension of the file name,
+// except for the first version.
+func versionedFileName(fileName string, version int) string {
+	if version <= 1 {
+		return fileName
 	}
-	defer resp.Body.Close()
+	ext := filepath.Ext(fileName)
+	return fmt.Sprintf("%s.v%d%s", strings.TrimSuffix(fileName, ext), version, ext)
+}
 
-	if resp.StatusCode >= 400 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return diag.FromErr(fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody)))
+// uploadAttachment uploads body to a task and returns the new attachment's
+// ID.
+func uploadAttachment(ctx context.Context, client *TaskManagerClient, taskID int, fileName string, contentType string, body *attachmentBody) (string, error) {
+	var result map[string]interface{}
+	endpoint := fmt.Sprintf("api/tasks/%d/attachments", taskID)
+	if err := client.Upload(ctx, endpoint, fileName, contentType, body, body.size, &result); err != nil {
+		return "", err
 	}
 
-	log.Println("$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$ before unmarshal")
+	attachment, ok := result["attachment"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid response structure")
+	}
+	id, ok := attachment["ID"].(float64)
+	if !ok {
+		return "", fmt.Errorf("invalid response: missing 'id'")
+	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return diag.FromErr(err)
+	return fmt.Sprintf("%d", int(id)), nil
+}
+
+func resourceUpdateAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("keep_versions").(int) > 0 || d.HasChange("keep_versions") {
+		return updateAttachmentVersions(ctx, d, m)
 	}
 
-	log.Println("$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$$ after unmarshal")
+	// Without versions, content changes replace the attachment, so only a
+	// new source with the same content or a new name or type can reach
+	// here. The API cannot change those, so upload again before removing
+	// the old attachment.
+	if !isRenamed(d) {
+		return resourceReadAttachment(ctx, d, m)
+	}
+	client := m.(*TaskManagerClient)
+	oldID := currentAttachmentID(d)
 
-	if attachment, ok := result["attachment"].(map[string]interface{}); ok {
-		if id, ok := attachment["ID"].(float64); ok {
-			d.SetId(fmt.Sprintf("%d", int(id)))
-		} else {
-			return diag.FromErr(fmt.Errorf("invalid response: missing 'id'"))
-		}
-	} else {
-		return diag.FromErr(fmt.Errorf("invalid response structure"))
+	if diags := uploadAttachmentVersion(ctx, d, m, 1); diags.HasError() {
+		// Nothing changed, and the API does not report content_type, so
+		// the planned values must not reach the state.
+		d.Partial(true)
+		return diags
 	}
+	d.Set("version_ids", []string{currentAttachmentID(d)})
 
-	var url interface{} = filePath
-	d.Set("url", url)
+	if err := client.Delete("api/attachments/" + oldID); err != nil {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Previous attachment %s not deleted", oldID),
+			Detail:   fmt.Sprintf("The file was uploaded again as attachment %s, but removing the old one failed: %s", currentAttachmentID(d), err),
+		}}
+	}
 
 	return resourceReadAttachment(ctx, d, m)
 }
 
-func resourceUpdateAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	resourceDeleteAttachment(ctx, d, m)
+// updateAttachmentVersions uploads changed content, names or types as a new
+// version next to the previous ones, then deletes the oldest previous
+// versions beyond keep_versions.
+func updateAttachmentVersions(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
 
-	resourceCreateAttachment(ctx, d, m)
-	return nil
+	oldVersionIDs, _ := d.GetChange("version_ids")
+	versionIDs := stringListOrDefault(oldVersionIDs.([]interface{}), nil)
+	if len(versionIDs) == 0 {
+		versionIDs = []string{currentAttachmentID(d)}
+	}
+	oldVersion, _ := d.GetChange("version")
+	version := max(oldVersion.(int), 1)
+
+	if hasUploadedChange(d, "content_sha256") || isRenamed(d) || d.HasChange("encryption_key_fingerprint") {
+		if diags := uploadAttachmentVersion(ctx, d, m, version+1); diags.HasError() {
+			// Read does not recompute content_sha256, so the planned new
+			// version must not reach the state, or it would never be
+			// uploaded.
+			d.Partial(true)
+			return diags
+		}
+		versionIDs = append(versionIDs, currentAttachmentID(d))
+	}
+
+	var diags diag.Diagnostics
+	previous := versionIDs[:len(versionIDs)-1]
+	excess := len(previous) - d.Get("keep_versions").(int)
+	kept := []string{}
+	for i, id := range previous {
+		if i >= excess {
+			kept = append(kept, id)
+			continue
+		}
+		// A version deleted outside Terraform is already gone.
+		if err := client.Delete("api/attachments/" + id); err != nil && !isNotFound(err) {
+			// Kept in version_ids, so the next apply tries again.
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Old version %s not deleted", id),
+				Detail:   fmt.Sprintf("Deleting attachment %s beyond keep_versions failed: %s", id, err),
+			})
+			kept = append(kept, id)
+		}
+	}
+	d.Set("version_ids", append(kept, currentAttachmentID(d)))
+
+	return append(diags, resourceReadAttachment(ctx, d, m)...)
 }
 
 func resourceReadAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
+	current := currentAttachmentID(d)
+	result, err := getAttachment(client, current)
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] attachment %s has been deleted, removing it from state", current)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Later versions and encrypted content are stored under a different
+	// name, which is not a change of file_name.
+	fileName := d.Get("file_name").(string)
+	uploadName := versionedFileName(fileName, d.Get("version").(int))
+	if d.Get("encryption_key_fingerprint").(string) != "" {
+		uploadName += encryptedSuffix
+	}
+	flattenAttachment(d, result)
+	if d.Get("file_name") == uploadName {
+		d.Set("file_name", fileName)
+	}
+
+	// Previous versions deleted outside Terraform are dropped.
+	versionIDs := []string{}
+	for _, id := range stringListOrDefault(d.Get("version_ids").([]interface{}), nil) {
+		if id == current {
+			continue
+		}
+		version, err := getAttachment(client, id)
+		if isNotFound(err) || (err == nil && isSoftDeleted(version)) {
+			log.Printf("[WARN] version %s of attachment %s has been deleted", id, current)
+			continue
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		versionIDs = append(versionIDs, id)
+	}
+	d.Set("attachment_id", current)
+	d.Set("version_ids", append(versionIDs, current))
+
+	return nil
+}
+
+// currentAttachmentID returns the ID of the attachment holding the current
+// version. The resource ID stays that of the first upload, so references to
+// it do not change when the attachment is uploaded again. State written
+// before attachment_id existed only has the resource ID.
+func currentAttachmentID(d *schema.ResourceData) string {
+	if id := d.Get("attachment_id").(string); id != "" {
+		return id
+	}
+	return d.Id()
+}
+
+func getAttachment(client *TaskManagerClient, id string) (map[string]interface{}, error) {
 	var attachment map[string]interface{}
+	if err := client.Get("api/attachments/"+id, &attachment); err != nil {
+		return nil, err
+	}
 
-	if err := client.Get("api/attachments/"+d.Id(), &attachment); err != nil {
-		return diag.FromErr(err)
+	result, ok := attachment["attachment"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get attachment %s", id)
 	}
 
-	result := attachment["attachment"].(map[string]interface{})
+	return result, nil
+}
 
-	d.Set("file_name", result["file_name"])
-	d.Set("task_id", result["task_id"])
-	if uploaderID, ok := result["uploader_id"].(float64); ok {
-		d.Set("uploader_id", int(uploaderID))
+// attachmentContentDiff works out the content of the attachment at plan
+// time, so changing it replaces the attachment even when the source argument
+// stays the same. It also fills in file_name and content_type when they are
+// not configured.
+func attachmentContentDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range attachmentSourceKeys {
+		if !d.NewValueKnown(key) {
+			return planUnknownContent(d)
+		}
+	}
+
+	src, err := expandAttachmentSource(d)
+	if err != nil {
+		return err
+	}
+
+	// Attachments created before content_sha256 was recorded have no hash
+	// in state. It is recorded without uploading them again, as what they
+	// were uploaded with is not known.
+	recordOnly := d.Id() != "" && d.Get("content_sha256").(string) == ""
+
+	fileName := d.Get("file_name").(string)
+	if !isConfigured(d, "file_name") {
+		fileName = src.fileName()
+		if err := setNewIfChanged(d, "file_name", fileName); err != nil {
+			return err
+		}
 	}
 
+	if src.remoteURL != "" {
+		return planRemoteContent(d, src)
+	}
+
+	body, err := src.open(ctx, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The file may be produced during apply, e.g. by another resource.
+		if recordOnly {
+			return nil
+		}
+		return planUnknownContent(d)
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	contentType := d.Get("content_type").(string)
+	if !isConfigured(d, "content_type") {
+		if contentType, err = body.contentType(fileName); err != nil {
+			return err
+		}
+		if err := setNewIfChanged(d, "content_type", contentType); err != nil {
+			return err
+		}
+	}
+
+	changed := d.Get("content_sha256").(string) != body.sha256
+	if (d.Id() == "" || (changed && !recordOnly) || isRenamed(d)) && d.NewValueKnown("file_name") && d.NewValueKnown("content_type") {
+		policy := m.(*TaskManagerClient).attachmentPolicy
+		if err := policy.check(fileName, contentType, body); err != nil {
+			return fmt.Errorf("%s: %w", src.key, err)
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := d.SetNew("content_sha256", body.sha256); err != nil {
+		return err
+	}
+	if err := d.SetNew("content_size", int(body.size)); err != nil {
+		return err
+	}
+	if recordOnly {
+		return nil
+	}
+	return planContentChange(d, "content_sha256")
+}
+
+// planRemoteContent plans a source_url attachment. Its content is only known
+// from source_sha256, without which the URL is not fetched again once the
+// attachment exists.
+func planRemoteContent(d *schema.ResourceDiff, src attachmentSource) error {
+	if src.remoteSHA256 == "" {
+		if d.Id() == "" || d.HasChanges("source_url", "source_sha256") {
+			return planUnknownContent(d)
+		}
+		return nil
+	}
+
+	if d.Get("content_sha256").(string) == src.remoteSHA256 {
+		return nil
+	}
+
+	if err := d.SetNew("content_sha256", src.remoteSHA256); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("content_size"); err != nil {
+		return err
+	}
+	if !isConfigured(d, "content_type") {
+		if err := d.SetNewComputed("content_type"); err != nil {
+			return err
+		}
+	}
+	return planContentChange(d, "content_sha256")
+}
+
+// planUnknownContent marks the content as known after apply, which replaces
+// an existing attachment.
+func planUnknownContent(d *schema.ResourceDiff) error {
+	keys := []string{"content_sha256", "content_size"}
+	for _, key := range []string{"file_name", "content_type"} {
+		if !isConfigured(d, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return planContentChange(d, "content_sha256")
+}
+
+// planContentChange plans new content for an existing attachment, after key
+// changed: a new version next to the previous ones with keep_versions,
+// otherwise a replacement.
+func planContentChange(d *schema.ResourceDiff, key string) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.NewValueKnown("keep_versions") && d.Get("keep_versions").(int) > 0 {
+		return planNewVersion(d)
+	}
+	return d.ForceNew(key)
+}
+
+// planAttachmentEncryption plans the key fingerprint of encrypted
+// attachments. Turning encryption on or off, or a new provider key, uploads
+// the content again.
+func planAttachmentEncryption(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("encrypt") {
+		return nil
+	}
+
+	fingerprint := ""
+	if d.Get("encrypt").(bool) {
+		enc := m.(*TaskManagerClient).attachmentEncryption
+		if !enc.canEncrypt() {
+			return fmt.Errorf("encrypt: the provider's attachment_encryption block needs age_recipients or aes_key_file")
+		}
+		fingerprint = enc.fingerprint
+	}
+
+	if d.Get("encryption_key_fingerprint").(string) == fingerprint {
+		return nil
+	}
+	if err := d.SetNew("encryption_key_fingerprint", fingerprint); err != nil {
+		return err
+	}
+	return planContentChange(d, "encryption_key_fingerprint")
+}
+
+// planAttachmentVersions plans a new version for a new name or type, which
+// is uploaded again like new content, and the pruning of previous versions
+// when keep_versions changes.
+func planAttachmentVersions(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if isRenamed(d) {
+		if d.Get("keep_versions").(int) > 0 {
+			return planNewVersion(d)
+		}
+		// Uploaded again under the new name or type.
+		return d.SetNewComputed("attachment_id")
+	}
+	if d.HasChange("keep_versions") {
+		return d.SetNewComputed("version_ids")
+	}
 	return nil
 }
 
+func planNewVersion(d *schema.ResourceDiff) error {
+	oldVersion, _ := d.GetChange("version")
+	if err := d.SetNew("version", max(oldVersion.(int), 1)+1); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("attachment_id"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("version_ids")
+}
+
+// attachmentChange is implemented by both schema.ResourceData and
+// schema.ResourceDiff, so plan and apply agree on what is uploaded again.
+type attachmentChange interface {
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+}
+
+// hasUploadedChange reports whether key changes from the value the
+// attachment was uploaded with. State written before content_sha256 and
+// content_type were recorded has them empty, and filling them in does not
+// upload the attachment again.
+func hasUploadedChange(d attachmentChange, key string) bool {
+	old, _ := d.GetChange(key)
+	return d.HasChange(key) && old.(string) != ""
+}
+
+// isRenamed reports whether the attachment needs uploading again under a new
+// name or type.
+func isRenamed(d attachmentChange) bool {
+	return hasUploadedChange(d, "file_name") || hasUploadedChange(d, "content_type")
+}
+
+func isConfigured(d *schema.ResourceDiff, key string) bool {
+	v, diags := d.GetRawConfigAt(cty.GetAttrPath(key))
+	return !diags.HasError() && !v.IsNull()
+}
+
+func setNewIfChanged(d *schema.ResourceDiff, key string, value interface{}) error {
+	if d.Get(key) == value {
+		return nil
+	}
+	return d.SetNew(key, value)
+}
+
+// fileDigest returns the hex SHA-256 and the size of a file.
+func fileDigest(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
 func resourceDeleteAttachment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	if err := client.Delete("api/attachments/" + d.Id()); err != nil {
+	// Previous versions go first, so a failure leaves the current one and
+	// the versions not deleted yet in state.
+	current := currentAttachmentID(d)
+	versionIDs := stringListOrDefault(d.Get("version_ids").([]interface{}), nil)
+	for i, id := range versionIDs {
+		if id == current {
+			continue
+		}
+		if err := client.Delete("api/attachments/" + id); err != nil && !isNotFound(err) {
+			d.Set("version_ids", versionIDs[i:])
+			return diag.Errorf("deleting previous version %s: %s", id, err)
+		}
+	}
+
+	if err := client.Delete("api/attachments/" + current); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -0,0 +1,533 @@
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAttachmentSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateAttachmentSet,
+		ReadContext:   resourceReadAttachmentSet,
+		UpdateContext: resourceUpdateAttachmentSet,
+		DeleteContext: resourceDeleteAttachmentSet,
+		CustomizeDiff: attachmentSetDiff,
+		Schema: map[string]*schema.Schema{
+			"task_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_dir": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"patterns": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"exclude": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntBetween(1, 32),
+			},
+			"file_hashes": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attachment_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceCreateAttachmentSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Nothing identifies the set on the backend, it is only the attachments
+	// it uploaded.
+	d.SetId(id.PrefixedUniqueId(fmt.Sprintf("%d-", d.Get("task_id").(int))))
+
+	diags := syncAttachmentSet(ctx, d, m, map[string]string{}, map[string]string{})
+	if !diags.HasError() {
+		return diags
+	}
+
+	// An error from Create taints the set, and replacing it would delete
+	// the files that were uploaded. Those are kept in state instead and the
+	// failures reported as warnings, so the next apply uploads the rest.
+	if len(stringMap(d.Get("attachment_ids"))) == 0 {
+		d.SetId("")
+		return diags
+	}
+	for i := range diags {
+		if diags[i].Severity == diag.Error {
+			diags[i].Severity = diag.Warning
+			diags[i].Detail = strings.TrimSpace(diags[i].Detail + " The next apply retries it.")
+		}
+	}
+	return diags
+}
+
+func resourceReadAttachmentSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	task, err := getTask(client, strconv.Itoa(d.Get("task_id").(int)))
+	if isNotFound(err) || (err == nil && isSoftDeleted(task)) {
+		log.Printf("[WARN] task of attachment set %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing := map[string]bool{}
+	if attachments, ok := task["attachments"].([]interface{}); ok {
+		for _, raw := range attachments {
+			attachment, ok := raw.(map[string]interface{})
+			if !ok || isSoftDeleted(attachment) {
+				continue
+			}
+			if id, ok := objectID(attachment); ok {
+				existing[strconv.Itoa(id)] = true
+			}
+		}
+	}
+
+	// Files whose attachment is gone are forgotten, so the next plan
+	// uploads them again.
+	hashes := stringMap(d.Get("file_hashes"))
+	ids := stringMap(d.Get("attachment_ids"))
+	for name, id := range ids {
+		if !existing[id] {
+			log.Printf("[WARN] attachment %s of %s has been deleted", id, name)
+			delete(ids, name)
+			delete(hashes, name)
+		}
+	}
+
+	d.Set("file_hashes", hashes)
+	d.Set("attachment_ids", ids)
+
+	return nil
+}
+
+func resourceUpdateAttachmentSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	oldHashes, _ := d.GetChange("file_hashes")
+	oldIDs, _ := d.GetChange("attachment_ids")
+
+	return syncAttachmentSet(ctx, d, m, stringMap(oldHashes), stringMap(oldIDs))
+}
+
+// syncAttachmentSet uploads new and changed files and deletes the
+// attachments of removed ones, starting from the files in hashes that were
+// uploaded as the attachments in ids. A changed file is uploaded before its
+// old attachment is deleted. Whatever succeeded is kept in state when some
+// transfers fail.
+func syncAttachmentSet(ctx context.Context, d *schema.ResourceData, m interface{}, hashes map[string]string, ids map[string]string) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	taskID := d.Get("task_id").(int)
+	sourceDir := d.Get("source_dir").(string)
+	planned := stringMap(d.Get("file_hashes"))
+
+	var uploads []string
+	var deletes []setDeletion
+	for name, sum := range planned {
+		if hashes[name] != sum || ids[name] == "" {
+			uploads = append(uploads, name)
+		}
+	}
+	for name, id := range ids {
+		if _, ok := planned[name]; !ok {
+			deletes = append(deletes, setDeletion{name: name, id: id})
+		}
+	}
+	sort.Strings(uploads)
+
+	var mu sync.Mutex
+	var diags diag.Diagnostics
+
+	parallel(d.Get("parallelism").(int), uploads, func(name string) {
+		id, err := uploadSetFile(ctx, client, taskID, sourceDir, name, planned[name])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			diags = append(diags, diag.Errorf("uploading %s: %s", name, err)...)
+			return
+		}
+		if oldID := ids[name]; oldID != "" {
+			deletes = append(deletes, setDeletion{name: name, id: oldID, replaced: true})
+		}
+		hashes[name] = planned[name]
+		ids[name] = id
+	})
+
+	parallel(d.Get("parallelism").(int), deletes, func(del setDeletion) {
+		name, oldID := del.name, del.id
+		err := client.Delete("api/attachments/" + oldID)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && del.replaced {
+			// The new attachment is in state already and the old one is
+			// not tracked anymore, so it is left behind.
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Previous attachment %s of %s not deleted", oldID, name),
+				Detail:   fmt.Sprintf("The file was uploaded again as attachment %s, but removing the old one failed: %s", ids[name], err),
+			})
+			return
+		}
+		if err != nil {
+			diags = append(diags, diag.Errorf("deleting attachment %s of %s: %s", oldID, name, err)...)
+			return
+		}
+		if !del.replaced {
+			delete(hashes, name)
+			delete(ids, name)
+		}
+	})
+
+	d.Set("file_hashes", hashes)
+	d.Set("attachment_ids", ids)
+
+	_, skipped, err := listSetFiles(sourceDir,
+		stringListOrDefault(d.Get("patterns").([]interface{}), []string{"**"}),
+		stringListOrDefault(d.Get("exclude").([]interface{}), nil),
+	)
+	if err == nil && len(skipped) > 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Files skipped",
+			Detail:   fmt.Sprintf("%s in %s match the patterns but are not regular files, such as symbolic links, and were not uploaded.", strings.Join(skipped, ", "), sourceDir),
+		})
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceReadAttachmentSet(ctx, d, m)...)
+}
+
+func resourceDeleteAttachmentSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	hashes := stringMap(d.Get("file_hashes"))
+	ids := stringMap(d.Get("attachment_ids"))
+	deletes := make([]setDeletion, 0, len(ids))
+	for name, id := range ids {
+		deletes = append(deletes, setDeletion{name: name, id: id})
+	}
+
+	var mu sync.Mutex
+	var diags diag.Diagnostics
+
+	parallel(d.Get("parallelism").(int), deletes, func(del setDeletion) {
+		err := client.Delete("api/attachments/" + del.id)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			diags = append(diags, diag.Errorf("deleting attachment %s of %s: %s", del.id, del.name, err)...)
+			return
+		}
+		delete(hashes, del.name)
+		delete(ids, del.name)
+	})
+
+	if diags.HasError() {
+		d.Set("file_hashes", hashes)
+		d.Set("attachment_ids", ids)
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// uploadSetFile uploads the file at name, relative to sourceDir, and checks
+// that it still has the content that was planned.
+func uploadSetFile(ctx context.Context, client *TaskManagerClient, taskID int, sourceDir string, name string, sum string) (string, error) {
+	src := attachmentSource{path: filepath.Join(sourceDir, filepath.FromSlash(name))}
+	body, err := src.open(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	if body.sha256 != sum {
+		return "", fmt.Errorf("file changed since the plan was made, run terraform apply again")
+	}
+
+	contentType, err := body.contentType(name)
+	if err != nil {
+		return "", err
+	}
+	if err := client.attachmentPolicy.check(name, contentType, body); err != nil {
+		return "", err
+	}
+
+	// The API drops the directory, so only the base name is sent.
+	return uploadAttachment(ctx, client, taskID, path.Base(name), contentType, body)
+}
+
+// attachmentSetDiff hashes the matching files at plan time, so new, changed
+// and removed files show up in the plan.
+func attachmentSetDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"source_dir", "patterns", "exclude"} {
+		if !d.NewValueKnown(key) {
+			if err := d.SetNewComputed("file_hashes"); err != nil {
+				return err
+			}
+			return d.SetNewComputed("attachment_ids")
+		}
+	}
+
+	hashes, err := hashSetFiles(
+		d.Get("source_dir").(string),
+		stringListOrDefault(d.Get("patterns").([]interface{}), []string{"**"}),
+		stringListOrDefault(d.Get("exclude").([]interface{}), nil),
+	)
+	if err != nil {
+		return err
+	}
+	if err := checkSetFileNames(hashes); err != nil {
+		return fmt.Errorf("source_dir: %w", err)
+	}
+
+	old := stringMap(d.Get("file_hashes"))
+	var changed []string
+	for name, sum := range hashes {
+		if old[name] != sum {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 && len(old) == len(hashes) {
+		return nil
+	}
+
+	sort.Strings(changed)
+	if err := checkSetFiles(m.(*TaskManagerClient).attachmentPolicy, d.Get("source_dir").(string), changed); err != nil {
+		return fmt.Errorf("source_dir: %w", err)
+	}
+
+	if err := d.SetNew("file_hashes", hashes); err != nil {
+		return err
+	}
+	return d.SetNewComputed("attachment_ids")
+}
+
+// checkSetFileNames rejects files with the same name in different
+// directories. The API keeps only the base name of an uploaded file, so they
+// could not be told apart on the task.
+func checkSetFileNames(hashes map[string]string) error {
+	byBase := map[string][]string{}
+	for name := range hashes {
+		base := path.Base(name)
+		byBase[base] = append(byBase[base], name)
+	}
+
+	var clashes []string
+	for _, names := range byBase {
+		if len(names) > 1 {
+			sort.Strings(names)
+			clashes = append(clashes, strings.Join(names, ", "))
+		}
+	}
+	if len(clashes) == 0 {
+		return nil
+	}
+	sort.Strings(clashes)
+	return fmt.Errorf("files are attached under their base name, which must be unique, but these share one: %s; use exclude to leave some out", strings.Join(clashes, "; "))
+}
+
+// checkSetFiles checks new and changed files of a set against the policy, so
+// violations fail the plan rather than the apply.
+func checkSetFiles(policy attachmentPolicy, dir string, names []string) error {
+	for _, name := range names {
+		body, err := attachmentSource{path: filepath.Join(dir, filepath.FromSlash(name))}.open(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+
+		contentType, err := body.contentType(name)
+		if err == nil {
+			err = policy.check(name, contentType, body)
+		}
+		body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashSetFiles returns the SHA-256 of every regular file below dir that
+// matches one of patterns and none of exclude, keyed by its slash-separated
+// path relative to dir.
+func hashSetFiles(dir string, patterns []string, exclude []string) (map[string]string, error) {
+	names, skipped, err := listSetFiles(dir, patterns, exclude)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		log.Printf("[WARN] attachment set skips %s in %s, which are not regular files", strings.Join(skipped, ", "), dir)
+	}
+
+	hashes := map[string]string{}
+	for _, name := range names {
+		sum, _, err := fileDigest(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		hashes[name] = sum
+	}
+	return hashes, nil
+}
+
+// listSetFiles returns the slash-separated paths relative to dir of the
+// regular files that match one of patterns and none of exclude. Matching
+// entries that are neither regular files nor directories, such as symbolic
+// links, are returned as skipped: following links could leave dir or loop.
+func listSetFiles(dir string, patterns []string, exclude []string) ([]string, []string, error) {
+	for _, pattern := range append(append([]string{}, patterns...), exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var names, skipped []string
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !matchesAnyGlob(name, patterns) || matchesAnyGlob(name, exclude) {
+			return nil
+		}
+
+		if entry.Type().IsRegular() {
+			names = append(names, name)
+		} else {
+			skipped = append(skipped, name)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("source_dir: %w", err)
+	}
+
+	return names, skipped, err
+}
+
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments as path.Match
+// does, where a "**" segment also matches any number of segments.
+func matchGlob(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
+
+// setDeletion is an attachment of an attachment set to delete, either
+// because its file was removed or because it was replaced by a new upload.
+type setDeletion struct {
+	name     string
+	id       string
+	replaced bool
+}
+
+// parallel calls fn for every item, running at most limit calls at a time.
+func parallel[T any](limit int, items []T, fn func(T)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(item)
+		}(item)
+	}
+
+	wg.Wait()
+}
+
+func stringMap(raw interface{}) map[string]string {
+	values := map[string]string{}
+	if m, ok := raw.(map[string]interface{}); ok {
+		for k, v := range m {
+			values[k] = v.(string)
+		}
+	}
+	return values
+}
@@ -0,0 +1,34 @@
+package taskmanager
+
+import "testing"
+
+func TestMatchesAnyGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.png", "logo.png", true},
+		{"*.png", "icons/logo.png", false},
+		{"**", "logo.png", true},
+		{"**", "icons/small/logo.png", true},
+		{"**/*.png", "logo.png", true},
+		{"**/*.png", "icons/small/logo.png", true},
+		{"**/*.png", "icons/logo.svg", false},
+		{"icons/**", "icons/logo.png", true},
+		{"icons/**", "icons/small/logo.png", true},
+		{"icons/**", "fonts/icons/logo.png", false},
+		{"icons/**/logo.png", "icons/logo.png", true},
+		{"icons/**/logo.png", "icons/a/b/logo.png", true},
+		{"icons/**/logo.png", "icons/a/b/logo.svg", false},
+		{"**/small/*", "icons/small/logo.png", true},
+		{"**/small/*", "icons/small/x/logo.png", false},
+		{"icons/*/logo.png", "icons/a/b/logo.png", false},
+	}
+
+	for _, tc := range cases {
+		if got := matchesAnyGlob(tc.name, []string{tc.pattern}); got != tc.match {
+			t.Fatalf("%q against %q: got %t, expected %t", tc.name, tc.pattern, got, tc.match)
+		}
+	}
+}
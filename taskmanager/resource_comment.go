@@ -16,7 +16,7 @@ func resourceComment() *schema.Resource {
 		UpdateContext: resourceUpdateComment,
 		ReadContext:   resourceReadComment,
 		DeleteContext: resourceDeleteComment,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"content": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -41,7 +41,7 @@ func resourceComment() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -97,36 +97,37 @@ func resourceUpdateComment(ctx context.Context, d *schema.ResourceData, m interf
 func resourceReadComment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	var comment map[string]interface{}
-
 	log.Println("[INFO] Getting Comment")
 
-	if err := client.Get("api/comments/"+d.Id(), &comment); err != nil {
+	result, err := getComment(client, d.Id())
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] comment %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	result := comment["comment"].(map[string]interface{})
-
-	d.Set("content", result["content"])
-	d.Set("user_id", result["user_id"])
-	d.Set("task_id", result["task_id"])
-	d.Set("parent_comment_id", result["parent_comment_id"])
-
-	var subCommentIDs []int
-	if subCommentRaw, ok := result["subcomments"].(*schema.Set); ok {
-		for _, subCommant := range subCommentRaw.List() {
-			if subCommentMap, ok := subCommant.(map[string]interface{}); ok {
-				if idFloat, ok := subCommentMap["ID"].(float64); ok {
-					subCommentIDs = append(subCommentIDs, int(idFloat))
-				}
-			}
-		}
-	}
-	d.Set("subcomments", subCommentIDs)
+	flattenComment(d, result)
 
 	return nil
 }
 
+func getComment(client *TaskManagerClient, id string) (map[string]interface{}, error) {
+	var comment map[string]interface{}
+	if err := client.Get("api/comments/"+id, &comment); err != nil {
+		return nil, err
+	}
+
+	result, ok := comment["comment"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get comment %s", id)
+	}
+
+	return result, nil
+}
+
 func resourceDeleteComment(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
@@ -0,0 +1,140 @@
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLabel() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateLabel,
+		ReadContext:   resourceReadLabel,
+		UpdateContext: resourceUpdateLabel,
+		DeleteContext: resourceDeleteLabel,
+		Schema: withTimestamps(map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"color": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.StringMatch(regexp.MustCompile(`^#[0-9a-fA-F]{6}$`), "must be a hex color such as \"#d73a4a\""),
+				DiffSuppressFunc: suppressCaseDifference,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"team_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		}),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// expandLabel returns the label to send to the API. A label without team_id
+// is available to every team, so an unset team_id is sent as null to move a
+// label out of a team. Without color, the API picks one.
+func expandLabel(d *schema.ResourceData) map[string]interface{} {
+	label := map[string]interface{}{
+		"name":        d.Get("name").(string),
+		"description": d.Get("description").(string),
+		"team_id":     nil,
+	}
+
+	if color, ok := d.GetOk("color"); ok {
+		label["color"] = color.(string)
+	}
+	if teamID, ok := d.GetOk("team_id"); ok {
+		label["team_id"] = teamID.(int)
+	}
+
+	return label
+}
+
+func resourceCreateLabel(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	var created map[string]interface{}
+	if err := client.Post("api/labels", expandLabel(d), &created); err != nil {
+		return diag.FromErr(err)
+	}
+
+	result, ok := created["label"].(map[string]interface{})
+	if !ok {
+		return diag.Errorf("label field missing in API response: %+v", created)
+	}
+
+	id := fmt.Sprintf("%v", result["ID"])
+	d.SetId(id)
+
+	return resourceReadLabel(ctx, d, m)
+}
+
+func resourceUpdateLabel(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	if d.HasChanges("name", "color", "description", "team_id") {
+		var updated map[string]interface{}
+		if err := client.Put("api/labels/"+d.Id(), expandLabel(d), &updated); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadLabel(ctx, d, m)
+}
+
+func resourceReadLabel(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	result, err := getLabel(client, d.Id())
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] label %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	flattenLabel(d, result)
+
+	return nil
+}
+
+func getLabel(client *TaskManagerClient, id string) (map[string]interface{}, error) {
+	var outer map[string]interface{}
+	if err := client.Get("api/labels/"+id, &outer); err != nil {
+		return nil, err
+	}
+
+	result, ok := outer["label"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get label %s", id)
+	}
+
+	return result, nil
+}
+
+func resourceDeleteLabel(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	if err := client.Delete("api/labels/" + d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
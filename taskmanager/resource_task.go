@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -16,7 +18,13 @@ func resourceTask() *schema.Resource {
 		ReadContext:   resourceReadTask,
 		UpdateContext: resourceUpdateTask,
 		DeleteContext: resourceDeleteTask,
-		Schema: map[string]*schema.Schema{
+		CustomizeDiff: customdiff.All(
+			validateTaskParent,
+			validateAllowedValue("status", func(c *TaskManagerClient) []string { return c.allowedStatuses }),
+			validateAllowedValue("priority", func(c *TaskManagerClient) []string { return c.allowedPriorities }),
+			validateStatusTransition,
+		),
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -26,16 +34,20 @@ func resourceTask() *schema.Resource {
 				Optional: true,
 			},
 			"status": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDifference,
 			},
 			"priority": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDifference,
 			},
 			"due_date": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateDueDate,
+				DiffSuppressFunc: suppressEquivalentDueDate,
 			},
 			"creator_id": {
 				Type:     schema.TypeInt,
@@ -85,7 +97,7 @@ func resourceTask() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -101,13 +113,17 @@ func resourceCreateTask(ctx context.Context, d *schema.ResourceData, m interface
 		task["description"] = description.(string)
 	}
 	if status, ok := d.GetOk("status"); ok {
-		task["status"] = status.(string)
+		task["status"] = canonicalValue(status.(string), client.allowedStatuses)
 	}
 	if priority, ok := d.GetOk("priority"); ok {
-		task["priority"] = priority.(string)
+		task["priority"] = canonicalValue(priority.(string), client.allowedPriorities)
 	}
 	if due_date, ok := d.GetOk("due_date"); ok {
-		task["due_date"] = due_date.(string)
+		dueDate, err := normalizeDueDate(due_date.(string), client.defaultLocation)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		task["due_date"] = dueDate
 	}
 	if assignees, ok := d.GetOk("assignees"); ok {
 		task["assignee_ids"] = assignees.(*schema.Set).List()
@@ -141,176 +157,228 @@ func resourceCreateTask(ctx context.Context, d *schema.ResourceData, m interface
 func resourceUpdateTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	task := map[string]interface{}{
-		"title": d.Get("title").(string),
-	}
-	if description, ok := d.GetOk("description"); ok {
-		task["description"] = description.(string)
-	}
-	if status, ok := d.GetOk("status"); ok {
-		task["status"] = status.(string)
-	}
-	if priority, ok := d.GetOk("priority"); ok {
-		task["priority"] = priority.(string)
-	}
-	if due_date, ok := d.GetOk("due_date"); ok {
-		task["due_date"] = due_date.(string)
-	}
-	if creatorId, ok := d.GetOk("creator_id"); ok {
-		task["creator_id"] = creatorId.(int)
+	// applied records the steps that already reached the backend, so a
+	// failure further down can report them.
+	var applied []string
+
+	if d.HasChanges("title", "description", "status", "priority", "due_date", "creator_id") {
+		task := map[string]interface{}{
+			"title": d.Get("title").(string),
+		}
+		if description, ok := d.GetOk("description"); ok {
+			task["description"] = description.(string)
+		}
+		if status, ok := d.GetOk("status"); ok {
+			task["status"] = canonicalValue(status.(string), client.allowedStatuses)
+		}
+		if priority, ok := d.GetOk("priority"); ok {
+			task["priority"] = canonicalValue(priority.(string), client.allowedPriorities)
+		}
+		if due_date, ok := d.GetOk("due_date"); ok {
+			dueDate, err := normalizeDueDate(due_date.(string), client.defaultLocation)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			task["due_date"] = dueDate
+		} else if d.HasChange("due_date") {
+			task["due_date"] = nil
+		}
+		if creatorId, ok := d.GetOk("creator_id"); ok {
+			task["creator_id"] = creatorId.(int)
+		}
+
+		var updated map[string]interface{}
+		if err := client.Put("api/tasks/"+d.Id(), task, &updated); err != nil {
+			return taskUpdateFailed(ctx, d, m, "details", applied, err)
+		}
+		applied = append(applied, "details")
+
+		log.Println("some details got updated")
 	}
 
-	var updated map[string]interface{}
-	if err := client.Put("api/tasks/"+d.Id(), task, &updated); err != nil {
-		return diag.FromErr(err)
+	if d.HasChange("team_id") {
+		task := map[string]interface{}{
+			"team_id": d.Get("team_id").(int),
+		}
+		var updated map[string]interface{}
+		if err := client.Put("api/tasks/"+d.Id()+"/change-team", task, &updated); err != nil {
+			return taskUpdateFailed(ctx, d, m, "change-team", applied, err)
+		}
+		applied = append(applied, "change-team")
+
+		log.Println("teamId got updated")
 	}
 
-	log.Println("some details got updated")
+	if d.HasChange("assignees") {
+		task := map[string]interface{}{
+			"assignees": d.Get("assignees").(*schema.Set).List(),
+		}
+		var updated map[string]interface{}
+		if err := client.Put("api/tasks/"+d.Id()+"/add-assignee", task, &updated); err != nil {
+			return taskUpdateFailed(ctx, d, m, "add-assignee", applied, err)
+		}
+		applied = append(applied, "add-assignee")
 
-	task = map[string]interface{}{}
-	task["team_id"] = d.Get("team_id").(int)
-	updated = map[string]interface{}{}
-	if err := client.Put("api/tasks/"+d.Id()+"/change-team", task, &updated); err != nil {
-		return diag.FromErr(err)
+		log.Println("assignees got updated")
 	}
 
-	log.Println("teamId got updated")
+	if d.HasChange("parent_task_id") {
+		// A parent_task_id of 0 detaches the task back to the top level.
+		task := map[string]interface{}{
+			"parent_task_id": d.Get("parent_task_id").(int),
+		}
+		var updated map[string]interface{}
+		if err := client.Put("api/tasks/"+d.Id()+"/parent-id", task, &updated); err != nil {
+			return taskUpdateFailed(ctx, d, m, "parent-id", applied, err)
+		}
+		applied = append(applied, "parent-id")
 
-	task = map[string]interface{}{}
-	if assignees, ok := d.GetOk("assignees"); ok {
-		task["assignees"] = assignees.(*schema.Set).List()
+		log.Println("parentId got updated")
 	}
-	updated = map[string]interface{}{}
-	if err := client.Put("api/tasks/"+d.Id()+"/add-assignee", task, &updated); err != nil {
-		return diag.FromErr(err)
+
+	if d.HasChange("labels") {
+		task := map[string]interface{}{
+			"labels": d.Get("labels").(*schema.Set).List(),
+		}
+		var updated map[string]interface{}
+		if err := client.Put("api/tasks/"+d.Id()+"/add-labels", task, &updated); err != nil {
+			return taskUpdateFailed(ctx, d, m, "add-labels", applied, err)
+		}
+		applied = append(applied, "add-labels")
+
+		log.Println("labels got updated")
 	}
 
-	log.Println("parentId got updated")
+	return resourceReadTask(ctx, d, m)
+}
 
-	task = map[string]interface{}{}
-	var parentTaskID int
-	if parentTaskId, ok := d.GetOk("parent_task_id"); ok {
-		task["parent_task_id"] = parentTaskId.(int)
-		parentTaskID = parentTaskId.(int)
+// taskUpdateFailed is returned when one of the sequential update calls fails.
+// The steps before it are already applied on the backend, so the task is read
+// back to make the state match what the backend holds instead of the
+// configuration. If that read fails too, the prior state is kept.
+func taskUpdateFailed(ctx context.Context, d *schema.ResourceData, m interface{}, step string, applied []string, err error) diag.Diagnostics {
+	detail := err.Error()
+	if len(applied) > 0 {
+		detail += fmt.Sprintf("\n\nSteps already applied before the failure: %s.", strings.Join(applied, ", "))
 	}
-	if parentTaskID > 0 {
-		updated = map[string]interface{}{}
-		if err := client.Put("api/tasks/"+d.Id()+"/parent-id", task, &updated); err != nil {
-			return diag.FromErr(err)
-		}
+
+	diags := diag.Diagnostics{diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Updating task %s failed at the %s step", d.Id(), step),
+		Detail:   detail,
+	}}
+
+	if readDiags := resourceReadTask(ctx, d, m); readDiags.HasError() {
+		d.Partial(true)
+		return append(diags, readDiags...)
 	}
 
-	log.Println("labels got updated")
+	return diags
+}
 
-	task = map[string]interface{}{}
-	if labels, ok := d.GetOk("labels"); ok {
-		task["labels"] = labels.(*schema.Set).List()
+func resourceReadTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*TaskManagerClient)
+
+	result, err := getTask(client, d.Id())
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] task %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
 	}
-	updated = map[string]interface{}{}
-	if err := client.Put("api/tasks/"+d.Id()+"/add-labels", task, &updated); err != nil {
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceReadTask(ctx, d, m)
+	flattenTask(d, result, client)
+
+	return nil
 }
 
-func resourceReadTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceDeleteTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	var task map[string]interface{}
-	if err := client.Get("api/tasks/"+d.Id(), &task); err != nil {
+	if err := client.Delete("api/tasks/" + d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
 
-	result, ok := task["task"].(map[string]interface{})
-	if !ok {
-		return diag.Errorf("unable to get team")
+	d.SetId("")
+	return nil
+}
+
+// validateTaskParent rejects parent changes that would make a task its own
+// ancestor or attach it to a task of another team. The existing hierarchy is
+// walked through the API, starting from the new parent.
+func validateTaskParent(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Moving a subtask to another team is checked as well, as it would leave
+	// the parent's team just like a new parent in another team.
+	if !d.HasChanges("parent_task_id", "team_id") || !d.NewValueKnown("parent_task_id") {
+		return nil
 	}
 
-	d.Set("title", result["title"])
-	d.Set("description", result["description"])
-	d.Set("status", result["status"])
-	d.Set("priority", result["priority"])
-	d.Set("creator_id", result["creator_id"])
-	d.Set("team_id", result["team_id"])
-
-	var assigneeIDs []int
-	if assigneesRaw, ok := result["assignees"].([]interface{}); ok {
-		for _, assignee := range assigneesRaw {
-			if assigneeMap, ok := assignee.(map[string]interface{}); ok {
-				if idFloat, ok := assigneeMap["ID"].(float64); ok {
-					assigneeIDs = append(assigneeIDs, int(idFloat))
-				}
-			}
-		}
+	parentID := d.Get("parent_task_id").(int)
+	if parentID <= 0 {
+		return nil
 	}
-	sort.Ints(assigneeIDs)
-	d.Set("assignees", assigneeIDs)
-
-	d.Set("parent_task_id", result["parent_task_id"])
-
-	var subTaskIDs []int
-	if subTaskRaw, ok := result["subtasks"].([]interface{}); ok {
-		for _, subTask := range subTaskRaw {
-			if subTaskMap, ok := subTask.(map[string]interface{}); ok {
-				if idFloat, ok := subTaskMap["ID"].(float64); ok {
-					subTaskIDs = append(subTaskIDs, int(idFloat))
-				}
-			}
-		}
+	if d.Id() != "" && strconv.Itoa(parentID) == d.Id() {
+		return fmt.Errorf("parent_task_id: task %s cannot be its own parent", d.Id())
 	}
-	sort.Ints(subTaskIDs)
-	d.Set("subtasks", subTaskIDs)
-
-	var labelIDs []int
-	if labelRaw, ok := result["labels"].([]interface{}); ok {
-		for _, label := range labelRaw {
-			if labelMap, ok := label.(map[string]interface{}); ok {
-				if idFloat, ok := labelMap["ID"].(float64); ok {
-					labelIDs = append(labelIDs, int(idFloat))
-				}
-			}
-		}
+
+	client := m.(*TaskManagerClient)
+
+	parent, err := getTask(client, strconv.Itoa(parentID))
+	if err != nil {
+		return fmt.Errorf("parent_task_id: unable to read parent task %d: %w", parentID, err)
 	}
-	sort.Ints(labelIDs)
-	d.Set("labels", labelIDs)
-
-	var commentIDs []int
-	if commentRaw, ok := result["comments"].([]interface{}); ok {
-		for _, comment := range commentRaw {
-			if commentMap, ok := comment.(map[string]interface{}); ok {
-				if idFloat, ok := commentMap["ID"].(float64); ok {
-					commentIDs = append(commentIDs, int(idFloat))
-				}
+
+	if d.NewValueKnown("team_id") {
+		teamID := d.Get("team_id").(int)
+		if parentTeamID := intValue(parent["team_id"]); parentTeamID != teamID {
+			if !d.HasChange("parent_task_id") {
+				return fmt.Errorf("team_id: task %s is a subtask of task %d in team %d; set parent_task_id = 0 to detach it before moving it to team %d", d.Id(), parentID, parentTeamID, teamID)
 			}
+			return fmt.Errorf("parent_task_id: parent task %d belongs to team %d but this task is in team %d; set team_id = %d to move the task together with its new parent", parentID, parentTeamID, teamID, parentTeamID)
 		}
 	}
-	sort.Ints(commentIDs)
-	d.Set("comments", commentIDs)
-
-	var attachmantIDs []int
-	if attachmantRaw, ok := result["attachments"].([]interface{}); ok {
-		for _, attachmant := range attachmantRaw {
-			if attachmantMap, ok := attachmant.(map[string]interface{}); ok {
-				if idFloat, ok := attachmantMap["ID"].(float64); ok {
-					attachmantIDs = append(attachmantIDs, int(idFloat))
-				}
-			}
-		}
+
+	// A task that does not exist yet cannot be an ancestor of anything, and
+	// an unchanged parent was checked when it was set.
+	if d.Id() == "" || !d.HasChange("parent_task_id") {
+		return nil
 	}
-	sort.Ints(attachmantIDs)
-	d.Set("attachments", attachmantIDs)
 
-	return nil
+	path := []string{d.Id(), strconv.Itoa(parentID)}
+	visited := map[int]bool{parentID: true}
+	for current := parent; ; {
+		ancestorID := intValue(current["parent_task_id"])
+		if ancestorID <= 0 {
+			return nil
+		}
+
+		path = append(path, strconv.Itoa(ancestorID))
+		if strconv.Itoa(ancestorID) == d.Id() {
+			return fmt.Errorf("parent_task_id: moving task %s under task %d would create a cycle (%s)", d.Id(), parentID, strings.Join(path, " -> "))
+		}
+		if visited[ancestorID] {
+			return fmt.Errorf("parent_task_id: the existing hierarchy above task %d already contains a cycle (%s)", parentID, strings.Join(path, " -> "))
+		}
+		visited[ancestorID] = true
+
+		if current, err = getTask(client, strconv.Itoa(ancestorID)); err != nil {
+			return fmt.Errorf("parent_task_id: unable to read ancestor task %d: %w", ancestorID, err)
+		}
+	}
 }
 
-func resourceDeleteTask(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*TaskManagerClient)
+func getTask(client *TaskManagerClient, id string) (map[string]interface{}, error) {
+	var task map[string]interface{}
+	if err := client.Get("api/tasks/"+id, &task); err != nil {
+		return nil, err
+	}
 
-	if err := client.Delete("api/tasks/" + d.Id()); err != nil {
-		return diag.FromErr(err)
+	result, ok := task["task"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get task %s", id)
 	}
 
-	d.SetId("")
-	return nil
+	return result, nil
 }
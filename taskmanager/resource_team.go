@@ -3,7 +3,7 @@ package taskmanager
 import (
 	"context"
 	"fmt"
-	"sort"
+	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -16,7 +16,7 @@ func resourceTeam() *schema.Resource {
 		ReadContext:   resourceReadTeam,
 		UpdateContext: resourceUpdateTeam,
 		DeleteContext: resourceDeleteTeam,
-		Schema: map[string]*schema.Schema{
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -43,7 +43,7 @@ func resourceTeam() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -86,21 +86,23 @@ func resourceCreateTeam(ctx context.Context, d *schema.ResourceData, m interface
 func resourceUpdateTeam(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	team := map[string]interface{}{
-		"name": d.Get("name").(string),
-	}
+	if d.HasChanges("name", "description") {
+		team := map[string]interface{}{
+			"name": d.Get("name").(string),
+		}
 
-	if desc, ok := d.GetOk("description"); ok {
-		team["description"] = desc.(string)
-	}
+		if desc, ok := d.GetOk("description"); ok {
+			team["description"] = desc.(string)
+		}
 
-	var updated map[string]interface{}
-	if err := client.Put("api/teams/"+d.Id(), team, &updated); err != nil {
-		return diag.FromErr(err)
+		var updated map[string]interface{}
+		if err := client.Put("api/teams/"+d.Id(), team, &updated); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	if members, ok := d.GetOk("members"); ok {
-		if err := addMembers(client, members.([]interface{}), d); err != nil {
+	if d.HasChange("members") {
+		if err := addMembers(client, d.Get("members").([]interface{}), d); err != nil {
 			return diag.Diagnostics{diag.Diagnostic{
 				Severity: diag.Warning,
 				Summary:  "Members not updated",
@@ -115,49 +117,33 @@ func resourceUpdateTeam(ctx context.Context, d *schema.ResourceData, m interface
 func resourceReadTeam(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	var outer map[string]interface{}
-	if err := client.Get("api/teams/"+d.Id(), &outer); err != nil {
+	result, err := getTeam(client, d.Id())
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] team %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	result, ok := outer["team"].(map[string]interface{})
-	if !ok {
-		return diag.FromErr(fmt.Errorf("unable to get team"))
-	}
+	flattenTeam(d, result)
 
-	d.Set("name", result["name"])
-	d.Set("description", result["description"])
+	return nil
+}
 
-	if ownerID, ok := result["owner_id"].(float64); ok {
-		d.Set("owner_id", int(ownerID))
+func getTeam(client *TaskManagerClient, id string) (map[string]interface{}, error) {
+	var outer map[string]interface{}
+	if err := client.Get("api/teams/"+id, &outer); err != nil {
+		return nil, err
 	}
 
-	var memberIDs []int
-	if membersRaw, ok := result["members"].([]interface{}); ok {
-		for _, member := range membersRaw {
-			if memberMap, ok := member.(map[string]interface{}); ok {
-				if idFloat, ok := memberMap["ID"].(float64); ok {
-					memberIDs = append(memberIDs, int(idFloat))
-				}
-			}
-		}
-	}
-	sort.Ints(memberIDs)
-	d.Set("members", memberIDs)
-
-	var taskIDs []int
-	if tasksRaw, ok := result["tasks"].([]interface{}); ok {
-		for _, task := range tasksRaw {
-			if taskMap, ok := task.(map[string]interface{}); ok {
-				if idFloat, ok := taskMap["id"].(float64); ok {
-					taskIDs = append(taskIDs, int(idFloat))
-				}
-			}
-		}
+	result, ok := outer["team"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get team %s", id)
 	}
-	d.Set("tasks", taskIDs)
 
-	return nil
+	return result, nil
 }
 
 func resourceDeleteTeam(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
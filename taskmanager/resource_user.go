@@ -3,7 +3,9 @@ package taskmanager
 import (
 	"context"
 	"fmt"
+	"log"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"golang.org/x/crypto/bcrypt"
@@ -15,7 +17,8 @@ func resourceUser() *schema.Resource {
 		DeleteContext: resourceDeleteUser,
 		UpdateContext: resourceUpdateUser,
 		ReadContext:   resourceReadUser,
-		Schema: map[string]*schema.Schema{
+		CustomizeDiff: validateAllowedValue("role", func(c *TaskManagerClient) []string { return c.allowedRoles }),
+		Schema: withTimestamps(map[string]*schema.Schema{
 			"uname": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -29,21 +32,27 @@ func resourceUser() *schema.Resource {
 				Required: true,
 			},
 			"password": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					// If password has not changed or user didn't provide a new one, ignore it
-					if err := bcrypt.CompareHashAndPassword([]byte(old), []byte(new)); err != nil {
-						return false
-					} else {
-						return true
-					}
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_wo"},
+			},
+			"password_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"password"},
+			},
+			"password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"password_wo"},
 			},
 			"role": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDifference,
 			},
 			"teams": {
 				Type:     schema.TypeList,
@@ -87,7 +96,7 @@ func resourceUser() *schema.Resource {
 					Type: schema.TypeInt,
 				},
 			},
-		},
+		}),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -103,14 +112,17 @@ func resourceCreateUser(ctx context.Context, d *schema.ResourceData, m interface
 		"email": d.Get("email").(string),
 	}
 
-	password, ok := d.GetOk("password")
-	if !ok {
-		return diag.FromErr(fmt.Errorf("password field is missing"))
+	password, diags := userPassword(d)
+	if diags.HasError() {
+		return diags
 	}
-	user["password"] = password.(string)
+	if password == "" {
+		return diag.FromErr(fmt.Errorf("one of password or password_wo must be set"))
+	}
+	user["password"] = password
 
 	if role, ok := d.GetOk("role"); ok {
-		user["role"] = role.(string)
+		user["role"] = canonicalValue(role.(string), client.allowedRoles)
 	}
 
 	var created map[string]interface{}
@@ -137,14 +149,34 @@ func resourceCreateUser(ctx context.Context, d *schema.ResourceData, m interface
 func resourceUpdateUser(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	user := map[string]interface{}{
-		"uname": d.Get("uname").(string),
-		"name":  d.Get("name").(string),
-		"email": d.Get("email").(string),
-	}
-	updated := make(map[string]interface{})
-	if err := client.Put("api/users/"+d.Id(), user, &updated); err != nil {
-		return diag.FromErr(err)
+	if d.HasChanges("uname", "name", "email", "role", "password", "password_wo_version") {
+		user := map[string]interface{}{
+			"uname": d.Get("uname").(string),
+			"name":  d.Get("name").(string),
+			"email": d.Get("email").(string),
+		}
+		if d.HasChange("role") {
+			user["role"] = canonicalValue(d.Get("role").(string), client.allowedRoles)
+		}
+		if d.HasChanges("password", "password_wo_version") {
+			password, diags := userPassword(d)
+			if diags.HasError() {
+				d.Partial(true)
+				return diags
+			}
+			if password != "" {
+				user["password"] = password
+			}
+		}
+
+		updated := make(map[string]interface{})
+		if err := client.Put("api/users/"+d.Id(), user, &updated); err != nil {
+			// Read cannot tell whether the password changed, so the
+			// previous password and password_wo_version are kept in state
+			// for the next apply to try again.
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceReadUser(ctx, d, m)
@@ -154,92 +186,57 @@ func resourceUpdateUser(ctx context.Context, d *schema.ResourceData, m interface
 func resourceReadUser(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*TaskManagerClient)
 
-	user := make(map[string]interface{})
-	if err := client.Get("api/users/"+d.Id(), &user); err != nil {
+	result, err := getUser(client, d.Id())
+	if isNotFound(err) || (err == nil && isSoftDeleted(result)) {
+		log.Printf("[WARN] user %s has been deleted, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	result := user["user"].(map[string]interface{})
+	flattenUser(d, result)
 
-	d.Set("uname", result["uname"])
-	d.Set("name", result["name"])
-	d.Set("email", result["email"])
-	d.Set("password", result["password"])
-	d.Set("role", result["role"])
-
-	var teamIds []int
-	if teamsRaw, ok := result["teams"].(*schema.Set); ok {
-		for _, team := range teamsRaw.List() {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamIds = append(teamIds, int(teamId))
-				}
-			}
-		}
+	// Earlier versions copied the bcrypt hash from the API into state. Drop
+	// it, so only the configured password is ever kept.
+	if _, err := bcrypt.Cost([]byte(d.Get("password").(string))); err == nil {
+		d.Set("password", "")
 	}
-	d.Set("teams", teamIds)
 
-	var teamCreatedIds []int
-	if teamsRaw, ok := result["tasks_created"].(*schema.Set); ok {
-		for _, team := range teamsRaw.List() {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamCreatedIds = append(teamCreatedIds, int(teamId))
-				}
-			}
-		}
-	}
-	d.Set("tasks_created", teamCreatedIds)
+	return nil
+}
 
-	var teamAssignedIds []int
-	if teamsRaw, ok := result["tasks_assigned"].(*schema.Set); ok {
-		for _, team := range teamsRaw.List() {
-			if teamMap, ok := team.(map[string]interface{}); ok {
-				if teamId, ok := teamMap["ID"].(float64); ok {
-					teamAssignedIds = append(teamAssignedIds, int(teamId))
-				}
-			}
-		}
+func getUser(client *TaskManagerClient, id string) (map[string]interface{}, error) {
+	user := make(map[string]interface{})
+	if err := client.Get("api/users/"+id, &user); err != nil {
+		return nil, err
 	}
-	d.Set("tasks_assigned", teamAssignedIds)
 
-	var commentIds []int
-	if commentsRaw, ok := result["comments"].(*schema.Set); ok {
-		for _, comment := range commentsRaw.List() {
-			if commentMap, ok := comment.(map[string]interface{}); ok {
-				if commentId, ok := commentMap["ID"].(float64); ok {
-					commentIds = append(commentIds, int(commentId))
-				}
-			}
-		}
+	result, ok := user["user"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to get user %s", id)
 	}
-	d.Set("comments", commentIds)
 
-	var attachmentIds []int
-	if attachmentRaw, ok := result["attachments"].(*schema.Set); ok {
-		for _, attachment := range attachmentRaw.List() {
-			if attachmentMap, ok := attachment.(map[string]interface{}); ok {
-				if attachmentId, ok := attachmentMap["ID"].(float64); ok {
-					attachmentIds = append(attachmentIds, int(attachmentId))
-				}
-			}
-		}
+	return result, nil
+}
+
+// userPassword returns the configured password, taking it from the write-only
+// password_wo attribute when that is used instead of password.
+func userPassword(d *schema.ResourceData) (string, diag.Diagnostics) {
+	if password, ok := d.GetOk("password"); ok {
+		return password.(string), nil
 	}
-	d.Set("attachments", attachmentIds)
 
-	var notificationIds []int
-	if notificationsRaw, ok := result["notifications"].(*schema.Set); ok {
-		for _, notification := range notificationsRaw.List() {
-			if notificationMap, ok := notification.(map[string]interface{}); ok {
-				if notificationId, ok := notificationMap["ID"].(float64); ok {
-					notificationIds = append(notificationIds, int(notificationId))
-				}
-			}
-		}
+	passwordWO, diags := d.GetRawConfigAt(cty.GetAttrPath("password_wo"))
+	if diags.HasError() {
+		return "", diags
+	}
+	if passwordWO.IsNull() || !passwordWO.Type().Equals(cty.String) {
+		return "", nil
 	}
-	d.Set("notifications", notificationIds)
 
-	return nil
+	return passwordWO.AsString(), nil
 }
 
 func resourceDeleteUser(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
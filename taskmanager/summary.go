@@ -0,0 +1,182 @@
+package taskmanager
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Data sources expose related objects both as ID lists and as nested blocks
+// carrying the fields most modules need, so no extra lookups are required.
+
+func userSummarySchema() *schema.Schema {
+	return summarySchema(map[string]schema.ValueType{
+		"id":    schema.TypeInt,
+		"uname": schema.TypeString,
+		"name":  schema.TypeString,
+		"email": schema.TypeString,
+		"role":  schema.TypeString,
+	})
+}
+
+func teamSummarySchema() *schema.Schema {
+	return summarySchema(map[string]schema.ValueType{
+		"id":          schema.TypeInt,
+		"name":        schema.TypeString,
+		"description": schema.TypeString,
+		"owner_id":    schema.TypeInt,
+	})
+}
+
+func taskSummarySchema() *schema.Schema {
+	return summarySchema(map[string]schema.ValueType{
+		"id":       schema.TypeInt,
+		"title":    schema.TypeString,
+		"status":   schema.TypeString,
+		"priority": schema.TypeString,
+		"due_date": schema.TypeString,
+		"team_id":  schema.TypeInt,
+	})
+}
+
+func labelSummarySchema() *schema.Schema {
+	return summarySchema(map[string]schema.ValueType{
+		"id":    schema.TypeInt,
+		"name":  schema.TypeString,
+		"color": schema.TypeString,
+	})
+}
+
+func commentSummarySchema() *schema.Schema {
+	return summarySchema(map[string]schema.ValueType{
+		"id":                schema.TypeInt,
+		"user_id":           schema.TypeInt,
+		"author_uname":      schema.TypeString,
+		"content":           schema.TypeString,
+		"parent_comment_id": schema.TypeInt,
+	})
+}
+
+// summarySchema returns a computed list of nested objects with the given
+// computed fields.
+func summarySchema(fields map[string]schema.ValueType) *schema.Schema {
+	elem := make(map[string]*schema.Schema, len(fields))
+	for name, fieldType := range fields {
+		elem[name] = &schema.Schema{
+			Type:     fieldType,
+			Computed: true,
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: elem,
+		},
+	}
+}
+
+func flattenUserSummary(user map[string]interface{}) map[string]interface{} {
+	id, _ := objectID(user)
+	return map[string]interface{}{
+		"id":    id,
+		"uname": stringValue(user["uname"]),
+		"name":  stringValue(user["name"]),
+		"email": stringValue(user["email"]),
+		"role":  stringValue(user["role"]),
+	}
+}
+
+func flattenTeamSummary(team map[string]interface{}) map[string]interface{} {
+	id, _ := objectID(team)
+	return map[string]interface{}{
+		"id":          id,
+		"name":        stringValue(team["name"]),
+		"description": stringValue(team["description"]),
+		"owner_id":    intValue(team["owner_id"]),
+	}
+}
+
+func flattenTaskSummary(task map[string]interface{}) map[string]interface{} {
+	id, _ := objectID(task)
+	return map[string]interface{}{
+		"id":       id,
+		"title":    stringValue(task["title"]),
+		"status":   stringValue(task["status"]),
+		"priority": stringValue(task["priority"]),
+		"due_date": flattenDueDate(task["due_date"], "", nil),
+		"team_id":  intValue(task["team_id"]),
+	}
+}
+
+func flattenLabelSummary(label map[string]interface{}) map[string]interface{} {
+	id, _ := objectID(label)
+	return map[string]interface{}{
+		"id":    id,
+		"name":  stringValue(label["name"]),
+		"color": stringValue(label["color"]),
+	}
+}
+
+func flattenCommentSummary(comment map[string]interface{}) map[string]interface{} {
+	id, _ := objectID(comment)
+
+	author := stringValue(comment["author_uname"])
+	if user, ok := comment["user"].(map[string]interface{}); ok {
+		author = stringValue(user["uname"])
+	}
+
+	return map[string]interface{}{
+		"id":                id,
+		"user_id":           intValue(comment["user_id"]),
+		"author_uname":      author,
+		"content":           stringValue(comment["content"]),
+		"parent_comment_id": intValue(comment["parent_comment_id"]),
+	}
+}
+
+func flattenUserDetails(d *schema.ResourceData, user map[string]interface{}) {
+	d.Set("team_details", flattenSummaries(user["teams"], flattenTeamSummary))
+	d.Set("tasks_created_details", flattenSummaries(user["tasks_created"], flattenTaskSummary))
+	d.Set("tasks_assigned_details", flattenSummaries(user["tasks_assigned"], flattenTaskSummary))
+}
+
+func flattenTeamDetails(d *schema.ResourceData, team map[string]interface{}) {
+	d.Set("member_details", flattenSummaries(team["members"], flattenUserSummary))
+	d.Set("task_details", flattenSummaries(team["tasks"], flattenTaskSummary))
+}
+
+func flattenTaskDetails(d *schema.ResourceData, task map[string]interface{}) {
+	d.Set("assignee_details", flattenSummaries(task["assignees"], flattenUserSummary))
+	d.Set("label_details", flattenSummaries(task["labels"], flattenLabelSummary))
+	d.Set("subtask_details", flattenSummaries(task["subtasks"], flattenTaskSummary))
+	d.Set("comment_details", flattenSummaries(task["comments"], flattenCommentSummary))
+}
+
+// flattenSummaries applies flatten to each object of a related list, ordered
+// by ID like the matching ID list.
+func flattenSummaries(raw interface{}, flatten func(map[string]interface{}) map[string]interface{}) []interface{} {
+	objects, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	summaries := make([]interface{}, 0, len(objects))
+	for _, object := range objects {
+		if objectMap, ok := object.(map[string]interface{}); ok {
+			summaries = append(summaries, flatten(objectMap))
+		}
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].(map[string]interface{})["id"].(int) < summaries[j].(map[string]interface{})["id"].(int)
+	})
+
+	return summaries
+}
+
+// stringValue returns a JSON string, or "" when v is not a string.
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,60 @@
+package taskmanager
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// GORM models serialize their timestamps as CreatedAt, UpdatedAt and
+// DeletedAt. The snake_case forms are accepted for renamed fields.
+var (
+	createdAtKeys = []string{"CreatedAt", "created_at"}
+	updatedAtKeys = []string{"UpdatedAt", "updated_at"}
+	deletedAtKeys = []string{"DeletedAt", "deleted_at"}
+)
+
+// withTimestamps adds the computed created_at, updated_at and deleted_at
+// attributes to a resource or data source schema.
+func withTimestamps(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for _, key := range []string{"created_at", "updated_at", "deleted_at"} {
+		s[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	return s
+}
+
+func flattenTimestamps(d *schema.ResourceData, object map[string]interface{}) {
+	d.Set("created_at", formatTimestamp(object, createdAtKeys...))
+	d.Set("updated_at", formatTimestamp(object, updatedAtKeys...))
+	d.Set("deleted_at", formatTimestamp(object, deletedAtKeys...))
+}
+
+// isSoftDeleted reports whether the backend has soft-deleted the object. The
+// API may still return such objects, but they are gone for Terraform.
+func isSoftDeleted(object map[string]interface{}) bool {
+	_, deleted := timestampValue(object, deletedAtKeys...)
+	return deleted
+}
+
+// timestampValue returns the first of keys holding a non-zero RFC 3339
+// timestamp.
+func timestampValue(object map[string]interface{}, keys ...string) (time.Time, bool) {
+	for _, key := range keys {
+		if raw, ok := object[key].(string); ok && raw != "" {
+			if t, err := time.Parse(time.RFC3339, raw); err == nil && !t.IsZero() {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatTimestamp(object map[string]interface{}, keys ...string) string {
+	if t, ok := timestampValue(object, keys...); ok {
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package taskmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// defaultUploadTimeout is used when the provider does not set
+// upload_timeout.
+const defaultUploadTimeout = 30 * time.Minute
+
+// transferProgressStep is the share of an upload or download, in percent,
+// between two progress log lines.
+const transferProgressStep = 10
+
+// quoteEscaper escapes a file name for a Content-Disposition header, as
+// multipart.Writer.CreateFormFile does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Upload sends size bytes read from body as the multipart form field "file",
+// named fileName, and decodes the JSON response into result.
+//
+// The body is streamed rather than buffered, so uploads need little memory
+// regardless of their size. The multipart framing around the body is known
+// up front, which gives the request a Content-Length unless size is
+// negative.
+func (c *TaskManagerClient) Upload(ctx context.Context, endPoint string, fileName string, contentType string, body io.Reader, size int64, result interface{}) error {
+	var framing bytes.Buffer
+	writer := multipart.NewWriter(&framing)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(fileName)))
+	header.Set("Content-Type", contentType)
+	if _, err := writer.CreatePart(header); err != nil {
+		return fmt.Errorf("failed to create form part: %w", err)
+	}
+	head := bytes.Clone(framing.Bytes())
+
+	framing.Reset()
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close form: %w", err)
+	}
+	tail := bytes.Clone(framing.Bytes())
+
+	if c.uploadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.uploadTimeout)
+		defer cancel()
+	}
+
+	progress := &transferProgress{
+		verb:   "uploading",
+		reader: body,
+		name:   fileName,
+		total:  size,
+		start:  time.Now(),
+	}
+
+	req, err := c.newRequest(ctx, "POST", endPoint, io.MultiReader(bytes.NewReader(head), progress, bytes.NewReader(tail)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = -1
+	if size >= 0 {
+		req.ContentLength = int64(len(head)) + size + int64(len(tail))
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	log.Printf("[INFO] uploading %s (%d bytes) to %s", fileName, size, endPoint)
+
+	if err := c.do(req, result); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("upload of %s did not finish within %s: %w", fileName, c.uploadTimeout, err)
+		}
+		return err
+	}
+
+	log.Printf("[INFO] uploaded %s in %s", fileName, time.Since(progress.start).Round(time.Millisecond))
+	return nil
+}
+
+// transferProgress logs how much of a transfer has been read.
+type transferProgress struct {
+	verb   string
+	reader io.Reader
+	name   string
+	total  int64
+	read   int64
+	logged int64
+	start  time.Time
+}
+
+func (p *transferProgress) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+
+	if p.total > 0 {
+		percent := p.read * 100 / p.total
+		if percent >= p.logged+transferProgressStep {
+			p.logged = percent - percent%transferProgressStep
+			log.Printf("[DEBUG] %s %s: %d%% (%d of %d bytes, %s)", p.verb, p.name, percent, p.read, p.total, time.Since(p.start).Round(time.Second))
+		}
+	}
+
+	return n, err
+}
+
+// Download writes the body of a GET request for endPoint to w and returns the
+// response headers and the number of bytes written. Like uploads, downloads
+// are streamed and bounded by the upload timeout.
+func (c *TaskManagerClient) Download(ctx context.Context, endPoint string, w io.Writer) (http.Header, int64, error) {
+	if c.uploadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.uploadTimeout)
+		defer cancel()
+	}
+
+	req, err := c.newRequest(ctx, "GET", endPoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	progress := &transferProgress{
+		verb:   "downloading",
+		reader: resp.Body,
+		name:   endPoint,
+		total:  resp.ContentLength,
+		start:  time.Now(),
+	}
+
+	n, err := io.Copy(w, progress)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, n, fmt.Errorf("download of %s did not finish within %s: %w", endPoint, c.uploadTimeout, err)
+		}
+		return nil, n, err
+	}
+
+	log.Printf("[INFO] downloaded %s (%d bytes) in %s", endPoint, n, time.Since(progress.start).Round(time.Millisecond))
+	return resp.Header, n, nil
+}
@@ -0,0 +1,185 @@
+package taskmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// taskWorkflow is a status workflow configured on the provider. A workflow
+// with teamID 0 applies to every team that has no workflow of its own.
+type taskWorkflow struct {
+	teamID      int
+	statuses    []string
+	transitions map[string][]string
+}
+
+func workflowSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"team_id": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"statuses": {
+					Type:     schema.TypeList,
+					Required: true,
+					MinItems: 1,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"transition": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"from": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"to": {
+								Type:     schema.TypeList,
+								Required: true,
+								Elem: &schema.Schema{
+									Type: schema.TypeString,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandWorkflows(raw []interface{}) ([]taskWorkflow, error) {
+	var workflows []taskWorkflow
+	seen := map[int]bool{}
+
+	for _, r := range raw {
+		block := r.(map[string]interface{})
+
+		wf := taskWorkflow{
+			teamID:      block["team_id"].(int),
+			transitions: map[string][]string{},
+		}
+		if seen[wf.teamID] {
+			if wf.teamID == 0 {
+				return nil, fmt.Errorf("workflow: only one workflow may omit team_id")
+			}
+			return nil, fmt.Errorf("workflow: team %d has more than one workflow", wf.teamID)
+		}
+		seen[wf.teamID] = true
+
+		for _, s := range block["statuses"].([]interface{}) {
+			wf.statuses = append(wf.statuses, s.(string))
+		}
+
+		for _, t := range block["transition"].([]interface{}) {
+			transition := t.(map[string]interface{})
+
+			from := transition["from"].(string)
+			if err := checkAllowedValue("workflow.transition.from", from, wf.statuses); err != nil {
+				return nil, err
+			}
+			from = canonicalValue(from, wf.statuses)
+
+			for _, to := range transition["to"].([]interface{}) {
+				if err := checkAllowedValue("workflow.transition.to", to.(string), wf.statuses); err != nil {
+					return nil, err
+				}
+				wf.transitions[from] = append(wf.transitions[from], canonicalValue(to.(string), wf.statuses))
+			}
+		}
+
+		workflows = append(workflows, wf)
+	}
+
+	return workflows, nil
+}
+
+// workflowFor returns the workflow that applies to the team, or nil when no
+// workflow is configured for it.
+func (c *TaskManagerClient) workflowFor(teamID int) *taskWorkflow {
+	var fallback *taskWorkflow
+	for i := range c.workflows {
+		switch c.workflows[i].teamID {
+		case teamID:
+			return &c.workflows[i]
+		case 0:
+			fallback = &c.workflows[i]
+		}
+	}
+	return fallback
+}
+
+// doneStatus returns the status that finishes a task of the team: the last
+// status of its workflow, or else the last of allowed_statuses.
+func (c *TaskManagerClient) doneStatus(teamID int) string {
+	statuses := c.allowedStatuses
+	if wf := c.workflowFor(teamID); wf != nil {
+		statuses = wf.statuses
+	}
+	if len(statuses) == 0 {
+		return ""
+	}
+	return statuses[len(statuses)-1]
+}
+
+// validateStatusTransition rejects status changes that the team's workflow
+// does not allow, naming the states that may follow the current one.
+func validateStatusTransition(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("status") || !d.NewValueKnown("status") {
+		return nil
+	}
+
+	teamID := 0
+	if d.NewValueKnown("team_id") {
+		teamID = d.Get("team_id").(int)
+	}
+
+	wf := m.(*TaskManagerClient).workflowFor(teamID)
+	if wf == nil {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange("status")
+	oldStatus, newStatus := oldRaw.(string), newRaw.(string)
+	if newStatus == "" {
+		return nil
+	}
+
+	if err := checkAllowedValue("status", newStatus, wf.statuses); err != nil {
+		return err
+	}
+
+	// New tasks may start in any state of the workflow, and a task coming
+	// from a state outside of it (e.g. after a team move) may enter anywhere.
+	if d.Id() == "" || strings.EqualFold(oldStatus, newStatus) || checkAllowedValue("status", oldStatus, wf.statuses) != nil {
+		return nil
+	}
+
+	oldStatus = canonicalValue(oldStatus, wf.statuses)
+	next := wf.transitions[oldStatus]
+	for _, s := range next {
+		if strings.EqualFold(s, newStatus) {
+			return nil
+		}
+	}
+
+	if len(next) == 0 {
+		return fmt.Errorf("status: the workflow does not allow moving a task out of %q", oldStatus)
+	}
+
+	quoted := make([]string, len(next))
+	for i, s := range next {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Errorf("status: the workflow does not allow moving a task from %q to %q, allowed next states are %s", oldStatus, newStatus, strings.Join(quoted, ", "))
+}
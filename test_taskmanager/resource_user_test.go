@@ -37,10 +37,12 @@ resource "taskmanager_user" "user_new" {
   role     = "Member"
 }
 
+data "taskmanager_current_user" "me" {}
+
 resource "taskmanager_team" "team_new" {
   name        = "AT - TASKMANAGER NAME"
   description = "AT - TASKMANAGER DESC"
-  members     = [1, taskmanager_user.user_new.id]
+  members     = [data.taskmanager_current_user.me.id, taskmanager_user.user_new.id]
 }
 
 resource "taskmanager_task" "task_new" {
@@ -51,7 +53,7 @@ resource "taskmanager_task" "task_new" {
   due_date = "2025-06-25T18:30:00Z"
   team_id = taskmanager_team.team_new.id
   parent_task_id = 0
-  assignees = [1,taskmanager_user.user_new.id]
+  assignees = [data.taskmanager_current_user.me.id, taskmanager_user.user_new.id]
   labels = []
 }
 
@@ -64,7 +66,7 @@ resource "taskmanager_comment" "comment_new"{
 resource "taskmanager_attachment" "attachment_new"{
   file_name = "infralovers_courses.pdf"
   task_id = taskmanager_task.task_new.id
-  url = "./static/files/infralovers_courses.pdf"
+  source_path = "./static/files/infralovers_courses.pdf"
 }
 `)
 }